@@ -0,0 +1,206 @@
+package list
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+type textCodec[T any] struct {
+	sep   string
+	parse func(string) (T, error)
+}
+
+type binaryCodec[T any] struct {
+	write func(*bytes.Buffer, T)
+	read  func(*bytes.Reader) (T, error)
+}
+
+// SetTextCodec sets the separator used by MarshalText and UnmarshalText, and
+// the function used by UnmarshalText to parse each element. If parse is nil,
+// then UnmarshalText returns ErrNoCodec. If sep is empty, then both
+// MarshalText and UnmarshalText return ErrEmptySeparator, since the elements
+// could not be told apart. If no codec is set, MarshalText uses a comma as
+// separator.
+func (l *List[T]) SetTextCodec(sep string, parse func(string) (T, error)) {
+	l.text = &textCodec[T]{
+		sep:   sep,
+		parse: parse,
+	}
+}
+
+// MarshalText returns the elements of the list converted to string, as in
+// String, and joined with the separator set with SetTextCodec. Elements are not
+// escaped, so they should not contain the separator.
+func (l List[T]) MarshalText() ([]byte, error) {
+	sep := ","
+	if l.text != nil {
+		sep = l.text.sep
+	}
+	if sep == "" {
+		return nil, ErrEmptySeparator
+	}
+	f := l.StringFunc
+	if f == nil {
+		f = toString[T]
+	}
+
+	var b []byte
+	for i := range l.len {
+		if 0 < i {
+			b = append(b, sep...)
+		}
+		b = append(b, f(l.s[l.abs(i)])...)
+	}
+
+	return b, nil
+}
+
+// UnmarshalText clears the list, splits b with the separator set with
+// SetTextCodec, and appends the result of parsing each part with the function
+// also set with SetTextCodec. If b is empty, then the list is left empty. If
+// an error is returned, the list is left empty.
+func (l *List[T]) UnmarshalText(b []byte) error {
+	if l.text == nil || l.text.parse == nil {
+		return ErrNoCodec
+	}
+	if l.text.sep == "" {
+		return ErrEmptySeparator
+	}
+	l.Clear()
+	if len(b) == 0 {
+		return nil
+	}
+
+	rest, more := string(b), true
+	for i := 0; more; i++ {
+		var part string
+		part, rest, more = strings.Cut(rest, l.text.sep)
+		err := l.Grow(1)
+		if err == nil {
+			var v T
+			if v, err = l.text.parse(part); err == nil {
+				l.s[l.abs(l.len)] = v
+				l.len++
+				continue
+			}
+		}
+		l.Clear()
+		return fmt.Errorf("decode list element %d: %w", i, err)
+	}
+
+	return nil
+}
+
+// GobEncode encodes the elements of the list in order with encoding/gob.
+func (l List[T]) GobEncode() ([]byte, error) {
+	// encode both segments separately to avoid copying the elements
+	var b bytes.Buffer
+	enc := gob.NewEncoder(&b)
+	s1, s2 := l.Segments()
+	if err := enc.Encode(s1); err != nil {
+		return nil, fmt.Errorf("gob encode list: %w", err)
+	}
+	if err := enc.Encode(s2); err != nil {
+		return nil, fmt.Errorf("gob encode list: %w", err)
+	}
+
+	return b.Bytes(), nil
+}
+
+// GobDecode clears the list and reads the elements encoded with GobEncode. The
+// decoded elements are stored in a new slice allocated with AllocFunc, so
+// MaxCap is honored, and the back of the list will be at its beginning. If an
+// error is returned, the list is not modified.
+func (l *List[T]) GobDecode(b []byte) error {
+	var s1, s2 []T
+	dec := gob.NewDecoder(bytes.NewReader(b))
+	if err := dec.Decode(&s1); err != nil {
+		return fmt.Errorf("gob decode list: %w", err)
+	}
+	if err := dec.Decode(&s2); err != nil {
+		return fmt.Errorf("gob decode list: %w", err)
+	}
+	s, err := l.alloc(len(s1)+len(s2), -1)
+	if err != nil {
+		return fmt.Errorf("gob decode list: %w", err)
+	}
+	copy(s, s1)
+	copy(s[len(s1):], s2)
+
+	l.Clear()
+	l.free(s)
+	l.len = len(s1) + len(s2)
+
+	return nil
+}
+
+// SetBinaryCodec sets the functions used by MarshalBinary and UnmarshalBinary
+// to write and read each element. If either of them is nil, then the
+// corresponding method returns ErrNoCodec.
+func (l *List[T]) SetBinaryCodec(write func(*bytes.Buffer, T), read func(*bytes.Reader) (T, error)) {
+	l.binary = &binaryCodec[T]{
+		write: write,
+		read:  read,
+	}
+}
+
+// MarshalBinary writes the number of elements of the list as a uvarint, and
+// then each element, in order, with the write function set with
+// SetBinaryCodec.
+func (l List[T]) MarshalBinary() ([]byte, error) {
+	if l.binary == nil || l.binary.write == nil {
+		return nil, ErrNoCodec
+	}
+
+	var b bytes.Buffer
+	b.Write(binary.AppendUvarint(nil, uint64(l.len)))
+	for i := range l.len {
+		l.binary.write(&b, l.s[l.abs(i)])
+	}
+
+	return b.Bytes(), nil
+}
+
+// UnmarshalBinary clears the list and reads the elements written by
+// MarshalBinary, using the read function set with SetBinaryCodec. If an error
+// is returned, the list is left empty.
+func (l *List[T]) UnmarshalBinary(b []byte) error {
+	if l.binary == nil || l.binary.read == nil {
+		return ErrNoCodec
+	}
+	l.Clear()
+
+	r := bytes.NewReader(b)
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return fmt.Errorf("decode list length: %w", err)
+	}
+	if n > math.MaxInt {
+		return ErrTooLarge
+	}
+
+	for i := range int(n) {
+		err := l.Grow(1)
+		if err == nil {
+			var v T
+			if v, err = l.binary.read(r); err == nil {
+				l.s[l.abs(l.len)] = v
+				l.len++
+				continue
+			}
+		}
+		l.Clear()
+		return fmt.Errorf("decode list element %d: %w", i, err)
+	}
+	if r.Len() > 0 {
+		l.Clear()
+		return errors.New("decode list: unexpected data after last element")
+	}
+
+	return nil
+}
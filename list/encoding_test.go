@@ -0,0 +1,151 @@
+package list
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestList_Text(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input []int
+		text  string
+	}{
+		{input: []int{}, text: ""},
+		{input: []int{1}, text: "1"},
+		{input: []int{1, -2, 30}, text: "1,-2,30"},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, 2, 1, tc.input...)
+			b, err := l.MarshalText()
+			require.NoError(t, err)
+			require.Equal(t, tc.text, string(b))
+
+			l = New[int](nil, false)
+			require.ErrorIs(t, l.UnmarshalText(b), ErrNoCodec)
+			l.SetTextCodec(",", strconv.Atoi)
+			require.NoError(t, l.UnmarshalText(b))
+			assertState(t, l, l.Free(), tc.input)
+
+			b2, err := l.MarshalText()
+			require.NoError(t, err)
+			require.Equal(t, b, b2)
+		})
+	}
+
+	l := wrappedList(t, 2, 1, 1, 2, 3)
+	l.SetTextCodec(" | ", strconv.Atoi)
+	l.StringFunc = func(v int) string { return strconv.Itoa(v * 10) }
+	b, err := l.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "10 | 20 | 30", string(b))
+	b, err = l.Clone().MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "10 | 20 | 30", string(b), "clones should keep the codec")
+
+	require.ErrorContains(t, l.UnmarshalText([]byte("1 | a | 3")), "list element 1")
+	assertState(t, l, l.Cap(), nil)
+	require.Error(t, l.UnmarshalText([]byte("1,2")))
+	require.NoError(t, l.UnmarshalText([]byte("4 | 5")))
+	assertState(t, l, l.Free(), []int{4, 5})
+
+	// an empty separator would never consume the input
+	l.SetTextCodec("", func(string) (int, error) { return 0, nil })
+	_, err = l.MarshalText()
+	require.ErrorIs(t, err, ErrEmptySeparator)
+	require.ErrorIs(t, l.UnmarshalText([]byte("123")), ErrEmptySeparator)
+	assertState(t, l, l.Free(), []int{4, 5})
+}
+
+func TestList_Gob(t *testing.T) {
+	t.Parallel()
+
+	testCases := [][]string{
+		{},
+		{"a"},
+		{"a", "b", "c", "d", "e"},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			for back := range len(tc) + 2 {
+				src := wrappedList(t, back, 2, tc...)
+				var b bytes.Buffer
+				require.NoError(t, gob.NewEncoder(&b).Encode(src))
+
+				var freed [][]string
+				dst := wrappedList(t, 1, 1, "x", "y")
+				dst.FreeFunc = func(s []string) { freed = append(freed, s) }
+				require.NoError(t, gob.NewDecoder(&b).Decode(dst))
+				assertState(t, dst, dst.Free(), tc)
+				require.Zero(t, dst.back)
+				require.Equal(t, [][]string{{"", "", ""}}, freed)
+			}
+		})
+	}
+
+	l := wrappedList(t, 1, 1, "x", "y")
+	require.Error(t, l.GobDecode([]byte("not gob")))
+	assertState(t, l, 1, []string{"x", "y"})
+}
+
+func TestList_Binary(t *testing.T) {
+	t.Parallel()
+
+	write := func(b *bytes.Buffer, v uint32) {
+		b.Write(binary.LittleEndian.AppendUint32(nil, v))
+	}
+	read := func(r *bytes.Reader) (uint32, error) {
+		var v uint32
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return v, err
+	}
+
+	testCases := [][]uint32{
+		{},
+		{1},
+		{1, 1 << 31, 3, 0, 5},
+		make([]uint32, 200),
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			src := wrappedList(t, len(tc)/2, 2, tc...)
+			_, err := src.MarshalBinary()
+			require.ErrorIs(t, err, ErrNoCodec)
+			src.SetBinaryCodec(write, read)
+
+			b, err := src.MarshalBinary()
+			require.NoError(t, err)
+			header := len(binary.AppendUvarint(nil, uint64(len(tc))))
+			require.Len(t, b, 4*len(tc)+header)
+
+			dst := wrappedList[uint32](t, 1, 1, 9, 9)
+			require.ErrorIs(t, dst.UnmarshalBinary(b), ErrNoCodec)
+			dst.SetBinaryCodec(nil, read)
+			require.NoError(t, dst.UnmarshalBinary(b))
+			assertState(t, dst, dst.Free(), tc)
+		})
+	}
+
+	l := New[uint32](nil, false)
+	l.SetBinaryCodec(write, read)
+	for _, input := range [][]byte{
+		{},
+		{2, 1, 0, 0, 0},
+		{1, 1, 0, 0, 0, 7},
+		{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+	} {
+		require.Error(t, l.UnmarshalBinary(input), "input: %v", input)
+		assertState(t, l, l.Cap(), nil)
+	}
+}
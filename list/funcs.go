@@ -0,0 +1,160 @@
+package list
+
+// FromMap returns a new list with the result of calling f with each entry of
+// m. Since the iteration order of maps is not specified, cmp can be used to
+// sort the resulting list. If cmp is nil, the list is not sorted.
+func FromMap[K comparable, V, T any](m map[K]V, f func(K, V) T, cmp CompareFunc[T]) *List[T] {
+	s := make([]T, 0, len(m))
+	for k, v := range m {
+		s = append(s, f(k, v))
+	}
+	l := New(s, true)
+	if cmp != nil {
+		l.Ordered(cmp).Sort()
+	}
+
+	return l
+}
+
+// Concat returns a new list with the elements of a followed by the elements
+// of b, with no free space and the same customizations as a. Neither a nor b
+// are modified.
+func Concat[T any](a, b *List[T]) *List[T] {
+	s := make([]T, a.len+b.len)
+	wrapCopy(a.s, s, a.back, 0, a.len)
+	wrapCopy(b.s, s, b.back, a.len, b.len)
+
+	return a.newWith(s)
+}
+
+// Eq reports whether a and b are equal using the == operator. It's meant to be
+// used as the equality function of methods like RemoveAll.
+func Eq[T comparable](a, b T) bool { return a == b }
+
+// HasDuplicates returns whether any element of the list appears more than
+// once.
+func HasDuplicates[T comparable](l *List[T]) bool {
+	_, _, found := FirstDuplicate(l)
+	return found
+}
+
+// FirstDuplicate scans the list from the back and returns the first element
+// that is equal to a previous one, along with its position and true. If all
+// the elements are unique, it returns the zero value, -1 and false.
+func FirstDuplicate[T comparable](l *List[T]) (v T, i int, found bool) {
+	seen := make(map[T]struct{}, l.len)
+	for i := range l.len {
+		v := l.s[l.abs(i)]
+		if _, found := seen[v]; found {
+			return v, i, true
+		}
+		seen[v] = struct{}{}
+	}
+	return v, -1, false
+}
+
+// MergeIntervals coalesces the intervals in o into the minimal set of
+// intervals covering the same values. The start and end of each interval are
+// obtained with lo and hi, and are both considered part of it, so intervals
+// that overlap or touch each other are merged. New intervals are created with
+// f. The list is expected to be sorted by the start of the intervals.
+func MergeIntervals[T any](o Ordered[T], lo, hi func(T) int64, f func(lo, hi int64) T) []T {
+	if o.len == 0 {
+		return nil
+	}
+
+	var ret []T
+	first := o.s[o.abs(0)]
+	curLo, curHi := lo(first), hi(first)
+	for i := 1; i < o.len; i++ {
+		v := o.s[o.abs(i)]
+		vLo, vHi := lo(v), hi(v)
+		if vLo <= curHi {
+			curHi = max(curHi, vHi)
+			continue
+		}
+		ret = append(ret, f(curLo, curHi))
+		curLo, curHi = vLo, vHi
+	}
+
+	return append(ret, f(curLo, curHi))
+}
+
+// WindowMax returns the maximum element, according to cmp, of each window of
+// size consecutive elements of the list, in order. It's O(n) on the number of
+// elements, regardless of size. If size<1 or size>l.Len(), it returns nil.
+func WindowMax[T any](l *List[T], size int, cmp CompareFunc[T]) []T {
+	if size < 1 || l.len < size {
+		return nil
+	}
+
+	// monotonic deque of positions, whose elements are decreasing from back to
+	// front, so the maximum of the window is always at the back
+	dq := New(make([]int, size), false)
+	ret := make([]T, 0, l.len-size+1)
+	for i := range l.len {
+		v := l.s[l.abs(i)]
+		for j, ok := dq.Val(dq.len - 1); ok && cmp(l.s[l.abs(j)], v) <= 0; j, ok = dq.Val(dq.len - 1) {
+			dq.Pop()
+		}
+		dq.Push(i)
+		if j, _ := dq.Val(0); j <= i-size {
+			dq.Delete(0, 1)
+		}
+		if size-1 <= i {
+			j, _ := dq.Val(0)
+			ret = append(ret, l.s[l.abs(j)])
+		}
+	}
+
+	return ret
+}
+
+// Reduce calls f with each element of the list, from back to front, and the
+// value accumulated so far, starting with init. It returns the final
+// accumulated value.
+func Reduce[T, A any](l *List[T], init A, f func(acc A, v T) A) A {
+	for i := range l.len {
+		init = f(init, l.s[l.abs(i)])
+	}
+	return init
+}
+
+// ReduceRight is like Reduce, but goes from the front to the back of the list.
+func ReduceRight[T, A any](l *List[T], init A, f func(acc A, v T) A) A {
+	for i := l.len - 1; 0 <= i; i-- {
+		init = f(init, l.s[l.abs(i)])
+	}
+	return init
+}
+
+// Fold1 combines all the elements of the list from back to front, using the
+// first element as the initial accumulated value, and returns the result and
+// true. If the list is empty, it returns the zero value and false.
+func Fold1[T any](l *List[T], combine func(a, b T) T) (T, bool) {
+	acc, ok := l.Val(0)
+	if !ok {
+		return acc, false
+	}
+	for i := 1; i < l.len; i++ {
+		acc = combine(acc, l.s[l.abs(i)])
+	}
+	return acc, true
+}
+
+// IsPermutation returns whether perm has exactly n elements and they are all
+// the integers in the range [0, n), each appearing once. It's O(n) in both
+// time and space.
+func IsPermutation(perm []int, n int) bool {
+	if len(perm) != n {
+		return false
+	}
+	seen := make([]bool, n)
+	for _, v := range perm {
+		if v < 0 || n <= v || seen[v] {
+			return false
+		}
+		seen[v] = true
+	}
+	return true
+}
@@ -0,0 +1,236 @@
+package list
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFirstDuplicate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input []int
+		v, i  int
+		found bool
+	}{
+		{input: nil, i: -1},
+		{input: []int{1}, i: -1},
+		{input: []int{1, 2, 3, 4}, i: -1},
+
+		{input: []int{1, 2, 1}, v: 1, i: 2, found: true},
+		{input: []int{1, 2, 3, 3}, v: 3, i: 3, found: true},
+		{input: []int{5, 1, 2, 2, 1, 5}, v: 2, i: 3, found: true},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := New(tc.input, true)
+			v, i, found := FirstDuplicate(l)
+			assert.Equal(t, tc.v, v)
+			assert.Equal(t, tc.i, i)
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.found, HasDuplicates(l))
+		})
+	}
+}
+
+func TestFromMap(t *testing.T) {
+	t.Parallel()
+
+	m := map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}
+	f := func(k string, v int) string { return fmt.Sprint(k, v) }
+	expected := []string{"a1", "b2", "c3", "d4"}
+
+	l := FromMap(m, f, nil)
+	got := slices.Collect(l.Values())
+	slices.Sort(got)
+	assert.Equal(t, expected, got)
+
+	l = FromMap(m, f, cmp.Compare[string])
+	assert.Equal(t, expected, slices.Collect(l.Values()))
+
+	l = FromMap(map[string]int(nil), f, cmp.Compare[string])
+	assert.Equal(t, 0, l.Len())
+}
+
+func TestMergeIntervals(t *testing.T) {
+	t.Parallel()
+
+	type interval struct{ lo, hi int64 }
+	lo := func(v interval) int64 { return v.lo }
+	hi := func(v interval) int64 { return v.hi }
+	mk := func(lo, hi int64) interval { return interval{lo, hi} }
+	byStart := func(a, b interval) int { return cmp.Compare(a.lo, b.lo) }
+
+	testCases := []struct {
+		input, expected []interval
+	}{
+		{},
+		{input: []interval{{1, 3}}, expected: []interval{{1, 3}}},
+		{
+			input:    []interval{{1, 3}, {5, 7}, {9, 9}},
+			expected: []interval{{1, 3}, {5, 7}, {9, 9}},
+		},
+		{
+			input:    []interval{{1, 3}, {2, 4}, {6, 9}, {7, 8}},
+			expected: []interval{{1, 4}, {6, 9}},
+		},
+		{
+			input:    []interval{{1, 3}, {3, 5}, {5, 5}, {6, 7}},
+			expected: []interval{{1, 5}, {6, 7}},
+		},
+		{
+			input:    []interval{{1, 10}, {2, 3}, {4, 5}, {10, 12}},
+			expected: []interval{{1, 12}},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			o := New(tc.input, true).Ordered(byStart)
+			assert.Equal(t, tc.expected, MergeIntervals(o, lo, hi, mk))
+		})
+	}
+}
+
+func TestWindowMax(t *testing.T) {
+	t.Parallel()
+
+	naive := func(s []int, size int) []int {
+		if size < 1 || len(s) < size {
+			return nil
+		}
+		var ret []int
+		for i := 0; i+size <= len(s); i++ {
+			ret = append(ret, slices.Max(s[i:i+size]))
+		}
+		return ret
+	}
+
+	testCases := []struct {
+		input []int
+		size  int
+	}{
+		{input: nil, size: 1},
+		{input: []int{1, 2, 3}, size: 0},
+		{input: []int{1, 2, 3}, size: 4},
+		{input: []int{1, 2, 3}, size: 1},
+		{input: []int{1, 2, 3}, size: 3},
+		{input: []int{3, 2, 1}, size: 2},
+		{input: []int{1, 3, -1, -3, 5, 3, 6, 7}, size: 3},
+		{input: []int{4, 4, 4, 1, 4}, size: 2},
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for range 20 {
+		s := make([]int, r.Intn(50)+1)
+		for i := range s {
+			s[i] = r.Intn(20)
+		}
+		testCases = append(testCases, struct {
+			input []int
+			size  int
+		}{input: s, size: r.Intn(len(s)) + 1})
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, len(tc.input)/2, 3, tc.input...)
+			got := WindowMax(l, tc.size, cmp.Compare[int])
+			assert.Equal(t, naive(tc.input, tc.size), got)
+		})
+	}
+}
+
+func BenchmarkWindowMax(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{1 << 10, 1 << 14, 1 << 18} {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = r.Int()
+		}
+		l := New(s, true)
+
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			for range b.N {
+				WindowMax(l, 64, cmp.Compare[int])
+			}
+		})
+	}
+}
+
+func TestReduce(t *testing.T) {
+	t.Parallel()
+
+	l := wrappedList(t, 3, 1, 1, 2, 3, 4)
+	sum := func(acc, v int) int { return acc + v }
+	concat := func(acc string, v int) string { return acc + fmt.Sprint(v) }
+
+	assert.Equal(t, 10, Reduce(l, 0, sum))
+	assert.Equal(t, 10, ReduceRight(l, 0, sum))
+	assert.Equal(t, ">1234", Reduce(l, ">", concat))
+	assert.Equal(t, ">4321", ReduceRight(l, ">", concat))
+
+	l = New[int](nil, false)
+	assert.Equal(t, ">", Reduce(l, ">", concat))
+	assert.Equal(t, ">", ReduceRight(l, ">", concat))
+}
+
+func TestFold1(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input    []int
+		expected int
+		ok       bool
+	}{
+		{input: nil},
+		{input: []int{7}, expected: 7, ok: true},
+		{input: []int{3, 9, 1, 4}, expected: 9, ok: true},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, 3, 1, tc.input...)
+			v, ok := Fold1(l, func(a, b int) int { return max(a, b) })
+			assert.Equal(t, tc.expected, v)
+			assert.Equal(t, tc.ok, ok)
+		})
+	}
+
+	l := wrappedList(t, 3, 1, "a", "b", "c")
+	v, _ := Fold1(l, func(a, b string) string { return a + b })
+	assert.Equal(t, "abc", v)
+}
+
+func TestIsPermutation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		perm     []int
+		n        int
+		expected bool
+	}{
+		{perm: nil, n: 0, expected: true},
+		{perm: []int{0, 1, 2, 3}, n: 4, expected: true},
+		{perm: []int{2, 0, 3, 1}, n: 4, expected: true},
+
+		{perm: []int{2, 0, 2, 1}, n: 4},
+		{perm: []int{2, 0, 4, 1}, n: 4},
+		{perm: []int{2, 0, -1, 1}, n: 4},
+		{perm: []int{2, 0, 1}, n: 4},
+		{perm: []int{2, 0, 3, 1, 4}, n: 4},
+		{perm: nil, n: 1},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsPermutation(tc.perm, tc.n))
+		})
+	}
+}
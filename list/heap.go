@@ -62,9 +62,74 @@ func (h Heap[T]) Pop() T {
 	return heap.Pop(heapInterface[T](h)).(T)
 }
 
-// Remove removes and returns the element at index i from the heap.
-func (h Heap[T]) Remove(i int) T {
-	return heap.Remove(heapInterface[T](h), i).(T)
+// PushPop pushes v onto the heap and then removes and returns the minimum
+// element. If the heap is empty, or v is less than or equal to the minimum
+// element, then v itself is returned and the heap is not modified. Otherwise,
+// v replaces the minimum element, which is returned. It's more efficient than
+// calling Push followed by Pop, with a complexity of O(log n) where
+// n = h.Len().
+func (h Heap[T]) PushPop(v T) T {
+	if h.len == 0 {
+		return v
+	}
+	i := h.abs(0)
+	if top := h.s[i]; h.cmp(top, v) < 0 {
+		h.s[i] = v
+		h.Fix(0)
+		return top
+	}
+	return v
+}
+
+// ReplaceTop removes and returns the minimum element and true, and then pushes
+// v onto the heap. Unlike PushPop, the returned value is never v, even if it's
+// less than the minimum element. If the heap is empty, then v is pushed and it
+// returns the zero value and false. It's more efficient than calling Pop
+// followed by Push, with a complexity of O(log n) where n = h.Len().
+func (h Heap[T]) ReplaceTop(v T) (top T, ok bool) {
+	if h.len == 0 {
+		h.Push(v)
+		return top, false
+	}
+	i := h.abs(0)
+	top, h.s[i] = h.s[i], v
+	h.Fix(0)
+	return top, true
+}
+
+// Sorted returns a new list with the elements of the heap in ascending order,
+// without modifying the heap. It uses heapsort in the new list, so the only
+// allocation is that of the new list. The complexity is O(n log n) where
+// n = h.Len().
+func (h Heap[T]) Sorted() *List[T] {
+	l := h.Clone()
+	n := l.len
+
+	// repeatedly pop the greatest element and put it in the freed position
+	maxHeap := l.Heap(h.cmp.Inverse)
+	for maxHeap.len > 0 {
+		v := maxHeap.Pop()
+		l.s[l.abs(l.len)] = v
+	}
+	l.len = n
+
+	return l
+}
+
+// Peek returns the minimum element (according to Less) without removing it,
+// and true. If the heap is empty, it returns the zero value and false.
+func (h Heap[T]) Peek() (T, bool) {
+	return h.Val(0)
+}
+
+// Remove removes and returns the element at index i from the heap, and true.
+// If i is out of range, it returns the zero value and false. The complexity is
+// O(log n) where n = h.Len().
+func (h Heap[T]) Remove(i int) (v T, ok bool) {
+	if !h.elBound(i) {
+		return v, false
+	}
+	return heap.Remove(heapInterface[T](h), i).(T), true
 }
 
 // UnmarshalJSON clears the heap, reads a JSON Array as a list of elements, and
@@ -77,6 +142,61 @@ func (h Heap[T]) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// BoundedHeap keeps at most k elements, which are the k smallest ones that
+// were pushed into it according to its CompareFunc. Using an inverted
+// CompareFunc keeps the k largest elements instead.
+type BoundedHeap[T any] struct {
+	h Heap[T]
+	k int
+}
+
+// NewBoundedHeap creates a new BoundedHeap that keeps at most k elements,
+// using the given CompareFunc. Space for the k elements is allocated upfront.
+// If k<1, then no element is ever kept.
+func NewBoundedHeap[T any](cmp CompareFunc[T], k int) BoundedHeap[T] {
+	k = max(k, 0)
+	// the greatest element is kept at the top so it can be quickly evicted
+	return BoundedHeap[T]{
+		h: New(make([]T, k), false).Heap(cmp.Inverse),
+		k: k,
+	}
+}
+
+// Len returns the number of elements in the heap.
+func (b BoundedHeap[T]) Len() int { return b.h.len }
+
+// K returns the maximum number of elements that the heap can keep.
+func (b BoundedHeap[T]) K() int { return b.k }
+
+// Push adds v to the heap. If the heap already had k elements, then the
+// greatest element among them and v is discarded and returned along with true.
+// Otherwise, it returns the zero value and false. The complexity is O(log k).
+func (b BoundedHeap[T]) Push(v T) (discarded T, ok bool) {
+	if b.h.len < b.k {
+		b.h.Push(v)
+		return discarded, false
+	}
+	return b.h.PushPop(v), true
+}
+
+// Peek returns the greatest element in the heap, which is the next one to be
+// discarded, and true. If the heap is empty, it returns the zero value and
+// false.
+func (b BoundedHeap[T]) Peek() (T, bool) { return b.h.Peek() }
+
+// Pop removes and returns the greatest element in the heap, and true. If the
+// heap is empty, it returns the zero value and false.
+func (b BoundedHeap[T]) Pop() (v T, ok bool) {
+	if b.h.len == 0 {
+		return v, false
+	}
+	return b.h.Pop(), true
+}
+
+// ToSlice returns a new slice with the elements of the heap, in no particular
+// order.
+func (b BoundedHeap[T]) ToSlice() []T { return b.h.ToSlice() }
+
 type heapInterface[T any] Heap[T]
 
 func (h heapInterface[T]) Push(x any) { h.List.Push(x.(T)) }
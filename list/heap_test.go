@@ -0,0 +1,199 @@
+package list
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeap_Peek(t *testing.T) {
+	t.Parallel()
+
+	h := NewHeap(cmp.Compare[int])
+	v, ok := h.Peek()
+	assert.False(t, ok)
+	assert.Zero(t, v)
+
+	r := rand.New(rand.NewSource(1))
+	for range 100 {
+		h.Push(r.Intn(1000))
+	}
+	for h.Len() > 0 {
+		v, ok := h.Peek()
+		require.True(t, ok)
+		require.Equal(t, v, h.Pop())
+	}
+}
+
+func TestHeap_Remove(t *testing.T) {
+	t.Parallel()
+
+	h := NewHeap(cmp.Compare[int])
+	_, ok := h.Remove(0)
+	assert.False(t, ok)
+
+	r := rand.New(rand.NewSource(1))
+	var all []int
+	for range 100 {
+		v := r.Intn(1000)
+		all = append(all, v)
+		h.Push(v)
+	}
+
+	_, ok = h.Remove(-1)
+	assert.False(t, ok)
+	_, ok = h.Remove(h.Len())
+	assert.False(t, ok)
+
+	for _, i := range []int{50, 98, 0, 7} {
+		v, ok := h.Remove(i)
+		require.True(t, ok)
+		j := slices.Index(all, v)
+		require.NotEqual(t, -1, j)
+		all = slices.Delete(all, j, j+1)
+	}
+
+	slices.Sort(all)
+	got := make([]int, 0, h.Len())
+	for h.Len() > 0 {
+		got = append(got, h.Pop())
+	}
+	assert.Equal(t, all, got)
+}
+
+func TestHeap_PushPop(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	h, naive := NewHeap(cmp.Compare[int]), NewHeap(cmp.Compare[int])
+	require.Equal(t, 5, h.PushPop(5))
+	require.Equal(t, 0, h.Len())
+
+	for range 200 {
+		v := r.Intn(100)
+		if r.Intn(3) == 0 {
+			h.Push(v)
+			naive.Push(v)
+			continue
+		}
+		naive.Push(v)
+		require.Equal(t, naive.Pop(), h.PushPop(v))
+		require.Equal(t, naive.Len(), h.Len())
+	}
+
+	for h.Len() > 0 {
+		require.Equal(t, naive.Pop(), h.Pop())
+	}
+}
+
+func TestHeap_ReplaceTop(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	h, naive := NewHeap(cmp.Compare[int]), NewHeap(cmp.Compare[int])
+	top, ok := h.ReplaceTop(5)
+	require.False(t, ok)
+	require.Zero(t, top)
+	naive.Push(5)
+
+	for range 200 {
+		v := r.Intn(100)
+		if r.Intn(3) == 0 {
+			h.Push(v)
+			naive.Push(v)
+			continue
+		}
+		expected := naive.Pop()
+		naive.Push(v)
+		top, ok := h.ReplaceTop(v)
+		require.True(t, ok)
+		require.Equal(t, expected, top)
+		require.Equal(t, naive.Len(), h.Len())
+	}
+
+	for h.Len() > 0 {
+		require.Equal(t, naive.Pop(), h.Pop())
+	}
+}
+
+func TestBoundedHeap(t *testing.T) {
+	t.Parallel()
+
+	const k = 10
+	r := rand.New(rand.NewSource(1))
+	smallest := NewBoundedHeap(cmp.Compare[int], k)
+	largest := NewBoundedHeap(CompareFunc[int](cmp.Compare[int]).Inverse, k)
+	require.Equal(t, k, smallest.K())
+
+	all := make([]int, 10000)
+	for i := range all {
+		all[i] = r.Int()
+		smallest.Push(all[i])
+		largest.Push(all[i])
+		require.LessOrEqual(t, smallest.Len(), k)
+	}
+	slices.Sort(all)
+
+	got := smallest.ToSlice()
+	slices.Sort(got)
+	assert.Equal(t, all[:k], got)
+
+	got = largest.ToSlice()
+	slices.Sort(got)
+	assert.Equal(t, all[len(all)-k:], got)
+
+	v, ok := smallest.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, all[k-1], v)
+	v, ok = smallest.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, all[k-1], v)
+	assert.Equal(t, k-1, smallest.Len())
+}
+
+func TestBoundedHeap_Push(t *testing.T) {
+	t.Parallel()
+
+	h := NewBoundedHeap(cmp.Compare[int], 2)
+	_, ok := h.Push(5)
+	assert.False(t, ok)
+	_, ok = h.Push(3)
+	assert.False(t, ok)
+	v, ok := h.Push(4)
+	assert.True(t, ok)
+	assert.Equal(t, 5, v)
+	v, ok = h.Push(9)
+	assert.True(t, ok)
+	assert.Equal(t, 9, v)
+
+	h = NewBoundedHeap(cmp.Compare[int], 0)
+	v, ok = h.Push(1)
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, 0, h.Len())
+	_, ok = h.Pop()
+	assert.False(t, ok)
+}
+
+func TestHeap_Sorted(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 2, 17, 500} {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = r.Intn(100)
+		}
+		h := New(slices.Clone(s), true).Heap(cmp.Compare[int])
+		before := h.ToSlice()
+
+		got := h.Sorted()
+		slices.SortFunc(s, cmp.Compare[int])
+		assert.Equal(t, s, got.ToSlice())
+		assert.Equal(t, before, h.ToSlice(), "should not modify the heap")
+	}
+}
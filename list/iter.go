@@ -0,0 +1,204 @@
+package list
+
+import (
+	"fmt"
+	"iter"
+	"math/rand"
+)
+
+// Collect returns a new list with the values from seq. Like the builtin append,
+// it panics if the values would not fit in a slice.
+func Collect[T any](seq iter.Seq[T]) *List[T] {
+	l := new(List[T])
+	if err := l.AppendSeq(seq); err != nil {
+		// AllocDefault only fails with ErrTooLarge
+		panic(fmt.Errorf("collect: %w", err))
+	}
+	return l
+}
+
+// ReservoirSample returns a new list with k values from seq, chosen uniformly
+// at random using the default math/rand.Source, so that seq can be consumed
+// without holding all its values in memory. If seq yields less than k values,
+// then all of them are returned. If k<1, the list will be empty and seq is
+// not consumed.
+func ReservoirSample[T any](seq iter.Seq[T], k int) *List[T] {
+	return reservoirSample(rand.Intn, seq, k)
+}
+
+// ReservoirSampleRand is like ReservoirSample but allows specifying an
+// alternative *math/rand.Rand.
+func ReservoirSampleRand[T any](r *rand.Rand, seq iter.Seq[T], k int) *List[T] {
+	return reservoirSample(r.Intn, seq, k)
+}
+
+func reservoirSample[T any](intn func(int) int, seq iter.Seq[T], k int) *List[T] {
+	l := Make[T](k)
+	if k < 1 {
+		return l
+	}
+
+	i := 0
+	for v := range seq {
+		if i < k {
+			l.s[i] = v
+			l.len++
+		} else if j := intn(i + 1); j < k {
+			l.s[j] = v
+		}
+		i++
+	}
+
+	return l
+}
+
+// Zip returns an iterator over the pairs of elements at the same positions of
+// both lists, from their back to their front. It stops at the end of the
+// shortest list.
+func Zip[A, B any](a *List[A], b *List[B]) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		for i := 0; ; i++ {
+			va, okA := a.Val(i)
+			vb, okB := b.Val(i)
+			if !okA || !okB || !yield(va, vb) {
+				return
+			}
+		}
+	}
+}
+
+// AppendSeq appends the values from seq to the front of the list. More space
+// is allocated as needed with Grow, so the amortization strategy of AllocFunc
+// is honored. If an allocation fails, the values appended so far are kept and
+// the error is returned.
+func (l *List[T]) AppendSeq(seq iter.Seq[T]) error {
+	for v := range seq {
+		if err := l.Grow(1); err != nil {
+			return err
+		}
+		l.s[l.abs(l.len)] = v
+		l.len++
+	}
+	return nil
+}
+
+// All returns an iterator over the positions and elements of the list, from
+// its back to its front.
+func (l *List[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := 0; ; i++ {
+			v, ok := l.Val(i)
+			if !ok || !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the positions and elements of the list,
+// from its front to its back.
+func (l *List[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := l.len - 1; ; i-- {
+			v, ok := l.Val(i)
+			if !ok || !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the elements of the list, from its back to
+// its front.
+func (l *List[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := 0; ; i++ {
+			v, ok := l.Val(i)
+			if !ok || !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// TakeWhile returns an iterator over the elements of the list, from its back
+// to its front, that stops at the first element for which pred returns false.
+func (l *List[T]) TakeWhile(pred func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := 0; ; i++ {
+			v, ok := l.Val(i)
+			if !ok || !pred(v) || !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// DropWhile returns an iterator over the elements of the list, from its back
+// to its front, that skips the leading elements for which pred returns true,
+// and yields all the remaining ones.
+func (l *List[T]) DropWhile(pred func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		i := 0
+		for v, ok := l.Val(i); ok && pred(v); v, ok = l.Val(i) {
+			i++
+		}
+		for ; ; i++ {
+			v, ok := l.Val(i)
+			if !ok || !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Chunks returns an iterator over consecutive chunks of size elements of the
+// list, from its back to its front, except for the last one, which may have
+// less. Each chunk is a new slice that doesn't share memory with the list. If
+// size<1, then nothing is yielded.
+func (l *List[T]) Chunks(size int) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		if size < 1 {
+			return
+		}
+		for i := 0; i < l.len; i += size {
+			chunk := make([]T, min(size, l.len-i))
+			wrapCopy(l.s, chunk, l.abs(i), 0, len(chunk))
+			if !yield(chunk) {
+				return
+			}
+		}
+	}
+}
+
+// Windows returns an iterator over every window of size consecutive elements
+// of the list, from its back to its front, so l.Len()-size+1 windows are
+// yielded. The same slice is reused for every window, and is overwritten when
+// the iteration resumes, so it must be copied if it needs to be retained. If
+// size<1 or size>l.Len(), then nothing is yielded.
+func (l *List[T]) Windows(size int) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		if size < 1 || l.len < size {
+			return
+		}
+		w := make([]T, size)
+		wrapCopy(l.s, w, l.back, 0, size)
+		for i := size; yield(w) && i < l.len; i++ {
+			copy(w, w[1:])
+			w[size-1] = l.s[l.abs(i)]
+		}
+	}
+}
+
+// DrainSorted returns an iterator that pops and yields the elements of the
+// heap, so they are yielded in ascending order and the heap is emptied. If the
+// iteration is stopped early, the remaining elements are kept in the heap.
+func (h Heap[T]) DrainSorted() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for h.len > 0 {
+			if !yield(h.Pop()) {
+				return
+			}
+		}
+	}
+}
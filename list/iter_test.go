@@ -0,0 +1,371 @@
+package list
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollect(t *testing.T) {
+	t.Parallel()
+
+	l := Collect(maps.Keys(map[int]bool{1: true, 2: true, 3: true}))
+	s := l.ToSlice()
+	slices.Sort(s)
+	assert.Equal(t, []int{1, 2, 3}, s)
+
+	assert.Equal(t, 0, Collect(slices.Values([]int(nil))).Len())
+}
+
+func TestReservoirSample(t *testing.T) {
+	t.Parallel()
+
+	seq := func(n int) iter.Seq[int] {
+		return func(yield func(int) bool) {
+			for i := range n {
+				if !yield(i) {
+					return
+				}
+			}
+		}
+	}
+
+	assert.Equal(t, 0, ReservoirSample(seq(10), 0).Len())
+	assert.Equal(t, 0, ReservoirSample(seq(10), -1).Len())
+	assert.Equal(t, []int{0, 1, 2}, ReservoirSample(seq(3), 5).ToSlice())
+
+	got := ReservoirSample(seq(10), 4).ToSlice()
+	assert.Len(t, got, 4)
+	for _, v := range got {
+		assert.True(t, 0 <= v && v < 10)
+	}
+
+	const iterations, n, k = 20000, 10, 3
+	r := rand.New(rand.NewSource(1))
+	counts := make([]int, n)
+	for range iterations {
+		l := ReservoirSampleRand(r, seq(n), k)
+		require.Equal(t, k, l.Len())
+		require.False(t, HasDuplicates(l))
+		for v := range l.Values() {
+			counts[v]++
+		}
+	}
+	expected := float64(iterations*k) / n
+	for v, count := range counts {
+		assert.InEpsilon(t, expected, float64(count), 0.05, "element %d", v)
+	}
+}
+
+func TestZip(t *testing.T) {
+	t.Parallel()
+
+	keys := wrappedList(t, 2, 1, "a", "b", "c")
+	values := wrappedList(t, 4, 2, 1, 2, 3, 4, 5)
+
+	var gotK []string
+	var gotV []int
+	for k, v := range Zip(keys, values) {
+		gotK = append(gotK, k)
+		gotV = append(gotV, v)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, gotK)
+	assert.Equal(t, []int{1, 2, 3}, gotV)
+
+	gotV = nil
+	for v := range Zip(values, keys) {
+		gotV = append(gotV, v)
+	}
+	assert.Equal(t, []int{1, 2, 3}, gotV)
+
+	gotV = nil
+	for v := range Zip(values, values) {
+		gotV = append(gotV, v)
+		if v == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []int{1, 2}, gotV)
+
+	for range Zip(New[int](nil, false), values) {
+		t.Fatal("unexpected pair")
+	}
+}
+
+func TestList_AppendSeq(t *testing.T) {
+	t.Parallel()
+
+	const n = 10_000
+	seq := func(yield func(int) bool) {
+		for i := range n {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	l := wrappedList(t, 2, 1, -3, -2, -1)
+	require.NoError(t, l.AppendSeq(seq))
+	require.Equal(t, n+3, l.Len())
+	for i, v := range l.All() {
+		require.Equal(t, i-3, v)
+	}
+
+	errTest := errors.New("test error")
+	l = wrappedList(t, 2, 1, -3, -2, -1)
+	l.AllocFunc = func(int, int) ([]int, error) { return nil, errTest }
+	require.ErrorIs(t, l.AppendSeq(seq), errTest)
+	assert.Equal(t, []int{-3, -2, -1, 0}, l.ToSlice())
+}
+
+func TestList_AppendSeq_allocs(t *testing.T) {
+	s := make([]int, 10_000)
+	allocs := testing.AllocsPerRun(10, func() {
+		var l List[int]
+		_ = l.AppendSeq(slices.Values(s))
+	})
+	// AllocDefault grows 3/2 each time
+	assert.Less(t, allocs, 30.0)
+}
+
+func TestList_All(t *testing.T) {
+	t.Parallel()
+
+	l := wrappedList(t, 3, 2, 1, 2, 3, 4, 5)
+
+	var got []int
+	for i, v := range l.All() {
+		assert.Equal(t, i+1, v)
+		got = append(got, v)
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, got)
+
+	got = got[:0]
+	for _, v := range l.All() {
+		if v == 3 {
+			break
+		}
+		got = append(got, v)
+	}
+	assert.Equal(t, []int{1, 2}, got)
+
+	for range New[int](nil, false).All() {
+		t.Fatal("unexpected element in empty list")
+	}
+}
+
+func TestList_Backward(t *testing.T) {
+	t.Parallel()
+
+	l := wrappedList(t, 3, 2, 1, 2, 3, 4, 5)
+
+	var got []int
+	for i, v := range l.Backward() {
+		assert.Equal(t, i+1, v)
+		got = append(got, v)
+	}
+	assert.Equal(t, []int{5, 4, 3, 2, 1}, got)
+
+	got = got[:0]
+	for _, v := range l.Backward() {
+		if v == 3 {
+			break
+		}
+		got = append(got, v)
+	}
+	assert.Equal(t, []int{5, 4}, got)
+
+	for range New[int](nil, false).Backward() {
+		t.Fatal("unexpected element in empty list")
+	}
+}
+
+func TestList_Values(t *testing.T) {
+	t.Parallel()
+
+	l := wrappedList(t, 3, 2, 1, 2, 3, 4, 5)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, slices.Collect(l.Values()))
+
+	var got []int
+	for v := range l.Values() {
+		if v == 3 {
+			break
+		}
+		got = append(got, v)
+	}
+	assert.Equal(t, []int{1, 2}, got)
+
+	assert.Empty(t, slices.Collect(New[int](nil, false).Values()))
+}
+
+func TestList_TakeWhile(t *testing.T) {
+	t.Parallel()
+
+	l := wrappedList(t, 3, 2, 1, 2, 3, 4, 1)
+	lessThan := func(n int) func(int) bool {
+		return func(v int) bool { return v < n }
+	}
+
+	testCases := []struct {
+		pred       func(int) bool
+		take, drop []int
+	}{
+		{pred: lessThan(0), drop: []int{1, 2, 3, 4, 1}},
+		{pred: lessThan(3), take: []int{1, 2}, drop: []int{3, 4, 1}},
+		{pred: lessThan(5), take: []int{1, 2, 3, 4, 1}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			require.Equal(t, tc.take, slices.Collect(l.TakeWhile(tc.pred)))
+			require.Equal(t, tc.drop, slices.Collect(l.DropWhile(tc.pred)))
+		})
+	}
+
+	// early stop
+	var got []int
+	for v := range l.TakeWhile(lessThan(5)) {
+		got = append(got, v)
+		if v == 2 {
+			break
+		}
+	}
+	require.Equal(t, []int{1, 2}, got)
+
+	got = nil
+	for v := range l.DropWhile(lessThan(2)) {
+		got = append(got, v)
+		if v == 3 {
+			break
+		}
+	}
+	require.Equal(t, []int{2, 3}, got)
+
+	// pred is not called after stopping
+	var calls int
+	for range l.TakeWhile(func(int) bool { calls++; return calls < 3 }) {
+	}
+	require.Equal(t, 3, calls)
+}
+
+func TestList_Chunks(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5, 6, 7}
+
+	testCases := []struct {
+		size     int
+		expected [][]int
+	}{
+		{size: -1},
+		{size: 0},
+		{size: 1, expected: [][]int{{1}, {2}, {3}, {4}, {5}, {6}, {7}}},
+		{size: 3, expected: [][]int{{1, 2, 3}, {4, 5, 6}, {7}}},
+		{size: 7, expected: [][]int{testData}},
+		{size: 8, expected: [][]int{testData}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, 5, 2, testData...)
+			got := slices.Collect(l.Chunks(tc.size))
+			require.Equal(t, tc.expected, got)
+			for _, chunk := range got {
+				chunk[0] = 0
+			}
+			assertState(t, l, 2, testData)
+		})
+	}
+
+	l := wrappedList(t, 5, 2, testData...)
+	var got [][]int
+	for chunk := range l.Chunks(3) {
+		got = append(got, chunk)
+		break
+	}
+	require.Equal(t, [][]int{{1, 2, 3}}, got)
+
+	assert.Empty(t, slices.Collect(New[int](nil, false).Chunks(3)))
+}
+
+func TestList_Windows(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5, 6, 7}
+
+	testCases := []struct {
+		size        int
+		count       int
+		first, last []int
+	}{
+		{size: -1},
+		{size: 0},
+		{size: 8},
+		{size: 1, count: 7, first: []int{1}, last: []int{7}},
+		{size: 3, count: 5, first: []int{1, 2, 3}, last: []int{5, 6, 7}},
+		{size: 7, count: 1, first: testData, last: testData},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, 5, 2, testData...)
+			var got [][]int
+			for w := range l.Windows(tc.size) {
+				require.Len(t, w, tc.size)
+				got = append(got, slices.Clone(w))
+			}
+			require.Len(t, got, tc.count)
+			if tc.count > 0 {
+				require.Equal(t, tc.first, got[0])
+				require.Equal(t, tc.last, got[len(got)-1])
+				for j, w := range got {
+					require.Equal(t, testData[j:j+tc.size], w)
+				}
+			}
+			assertState(t, l, 2, testData)
+		})
+	}
+
+	l := wrappedList(t, 5, 2, testData...)
+	var count int
+	for range l.Windows(2) {
+		count++
+		if count == 3 {
+			break
+		}
+	}
+	require.Equal(t, 3, count)
+}
+
+func TestHeap_DrainSorted(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	h := NewHeap(cmp.Compare[int])
+	for range 100 {
+		h.Push(r.Intn(50))
+	}
+
+	var got []int
+	for v := range h.DrainSorted() {
+		got = append(got, v)
+		if len(got) == 30 {
+			break
+		}
+	}
+	require.Equal(t, 70, h.Len())
+	for v := range h.DrainSorted() {
+		got = append(got, v)
+	}
+	assert.Len(t, got, 100)
+	assert.True(t, slices.IsSorted(got))
+	assert.Equal(t, 0, h.Len())
+}
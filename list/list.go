@@ -2,11 +2,17 @@ package list
 
 import (
 	"bytes"
+	crand "crypto/rand"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
 	"math/rand"
+	"slices"
 	"strings"
+	"unsafe"
 )
 
 // Package level errors for List.
@@ -15,6 +21,10 @@ var (
 	ErrInvalidRange      = errors.New("invalid range")
 	ErrInvalidAmount     = errors.New("invalid amount of elements")
 	ErrInvalidAllocation = errors.New("insufficient space allocated")
+	ErrTooLarge          = errors.New("list would be too large")
+	ErrNotJSONArray      = errors.New("JSON value is not an array")
+	ErrNoCodec           = errors.New("no codec set")
+	ErrEmptySeparator    = errors.New("empty separator")
 )
 
 // AllocFunc is a function that allocates a new slice that needs to hold at
@@ -36,17 +46,42 @@ func FixAllocSize(newIntendedCap, maxCap int) int {
 	return newIntendedCap
 }
 
-// AllocDefault is the default AllocFunc[T].
+// AllocDefault is the default AllocFunc[T]. It returns ErrTooLarge if a slice
+// of min elements of type T would be larger than the runtime allows.
 func AllocDefault[T any](min, max int) ([]T, error) {
-	size := min*3/2 + 1
+	limit := maxSliceLen[T]()
+	if limit < min {
+		return nil, ErrTooLarge
+	}
+	size := min + min/2 + 1
+	if size < min || limit < size {
+		size = limit // overflow
+	}
 	size = FixAllocSize(size, max)
 	return make([]T, size), nil
 }
 
+// maxAllocBytes is the maximum size of a single allocation on 64-bit
+// platforms, as defined by the runtime. Larger allocations make the builtin
+// make panic.
+const maxAllocBytes uint64 = 1 << 48
+
+// maxSliceLen returns the maximum length of a []T that can be made without
+// panicking, regardless of the available memory.
+func maxSliceLen[T any]() int {
+	var zero T
+	size := uint64(unsafe.Sizeof(zero))
+	if size == 0 {
+		return math.MaxInt
+	}
+	return int(min(uint64(math.MaxInt), maxAllocBytes) / size)
+}
+
 // List is a slice-based list container, indexed from its back to its front
 // starting at zero. Not safe for concurrent use. It zeroes elements that are
-// removed from the list. The implementation is panic free, so errors are
-// returned for the cases where bounds and other input need to be validated.
+// removed from the list, unless NoZero is set. The implementation is panic
+// free, so errors are returned for the cases where bounds and other input need
+// to be validated.
 type List[T any] struct {
 	// AllocFunc allows customizing allocation of a new slice when more space
 	// is needed. If nil, AllocDefault is used.
@@ -62,6 +97,26 @@ type List[T any] struct {
 	// printing the list. The default is using fmt.Sprintf("%v", element).
 	StringFunc func(T) string
 
+	// NoZero disables zeroing the elements that are removed from the list.
+	// This saves time when T contains no pointers, since the memory will be
+	// overwritten anyway when new elements are added.
+	//
+	// WARNING: if T is or contains pointers, then setting NoZero keeps the
+	// removed elements reachable by the garbage collector for as long as the
+	// underlying slice is, which can cause memory leaks. Slices passed to
+	// FreeFunc will not be zeroed either, so they may also contain stale
+	// references.
+	NoZero bool
+
+	// MaxCap, if positive, is the maximum capacity that the list can have. It
+	// is passed as the max argument to AllocFunc, and operations that would
+	// need a larger capacity fail with ErrInvalidAllocation and leave the list
+	// unchanged. Zero means unbounded.
+	MaxCap int
+
+	text   *textCodec[T]
+	binary *binaryCodec[T]
+
 	s []T
 	view
 }
@@ -114,6 +169,31 @@ func NewN[T any](s []T, back, length int) (*List[T], error) {
 	}, nil
 }
 
+// Make creates a new empty List with the given capacity. If capacity<0, then
+// it's considered to be zero.
+func Make[T any](capacity int) *List[T] {
+	return New(make([]T, max(capacity, 0)), false)
+}
+
+// MakeN creates a new List with length elements set to fill, and the given
+// capacity. If length<0, then it's considered to be zero, and if
+// capacity<length, then it's considered to be length.
+func MakeN[T any](length, capacity int, fill T) *List[T] {
+	length = max(length, 0)
+	s := make([]T, max(capacity, length))
+	for i := range length {
+		s[i] = fill
+	}
+
+	return &List[T]{
+		s: s,
+		view: view{
+			slen: len(s),
+			len:  length,
+		},
+	}
+}
+
 // view is used to provide fast and inlineable arithmetic and checks while
 // still ergonomic.
 type view struct {
@@ -149,8 +229,8 @@ func (v view) fixAbs(i int) int {
 	if v.len == 0 {
 		return 0
 	}
-	i = fix(v.len, i)
-	if i > v.slen {
+	i = fix(v.len, i) + v.back
+	if i >= v.slen {
 		return i - v.slen
 	}
 	return i
@@ -160,6 +240,14 @@ func (v view) fixAbs(i int) int {
 func (v view) wraps() bool { return v.slen-v.back < v.len }
 
 func (l *List[T]) alloc(min, max int) ([]T, error) {
+	if 0 < l.MaxCap {
+		if l.MaxCap < min {
+			return nil, ErrInvalidAllocation
+		}
+		if max < 0 || l.MaxCap < max {
+			max = l.MaxCap
+		}
+	}
 	f := l.AllocFunc
 	if f == nil {
 		f = AllocDefault[T]
@@ -177,7 +265,7 @@ func (l *List[T]) alloc(min, max int) ([]T, error) {
 
 func (l *List[T]) free(newSlice []T) {
 	if l.FreeFunc != nil {
-		wrapClear(l.s, l.back, l.len)
+		l.wrapClear(l.back, l.len)
 		l.FreeFunc(l.s)
 	}
 	l.s = newSlice
@@ -215,25 +303,348 @@ func (l *List[T]) GrowRange(min, max int) error {
 	return l.grow(min, max)
 }
 
+// GrowExact makes sure that the list has capacity for at least n new
+// elements. If l.Free()<n, then a new slice with capacity for exactly n new
+// elements will be allocated and the list migrated to it.
+func (l *List[T]) GrowExact(n int) error {
+	if n < 0 {
+		return ErrInvalidAmount
+	}
+	if n <= l.Free() {
+		return nil
+	}
+
+	return l.grow(n, n)
+}
+
 func (l *List[T]) grow(min, max int) error {
 	if free := l.Free(); min <= free && (max < 0 || free <= max) {
 		return nil
 	}
 
-	needCap := l.len + (max-min)/2
-	if l.slen < needCap {
-		s, err := l.alloc(min, max)
-		if err != nil {
-			return err
+	if math.MaxInt-l.len < min {
+		return ErrTooLarge
+	}
+	if 0 <= max {
+		if math.MaxInt-l.len < max {
+			max = math.MaxInt
+		} else {
+			max += l.len
 		}
-		wrapCopy(l.s, s, l.back, 0, l.len)
-		l.free(s)
-		l.back = 0
+	}
+	s, err := l.alloc(l.len+min, max)
+	if err != nil {
+		return err
+	}
+	wrapCopy(l.s, s, l.back, 0, l.len)
+	l.free(s)
+	l.back = 0
+
+	return nil
+}
+
+// Compact migrates the list to a newly allocated slice of exactly Len()
+// elements, with its back at the beginning of the slice. It's a nop if the
+// list has no free space and doesn't wrap.
+func (l *List[T]) Compact() error {
+	if l.Free() == 0 && l.back == 0 {
+		return nil
 	}
 
+	s, err := l.alloc(l.len, l.len)
+	if err != nil {
+		return err
+	}
+	wrapCopy(l.s, s, l.back, 0, l.len)
+	l.free(s)
+	l.back = 0
+
 	return nil
 }
 
+// CompactTo calls Compact only if the list has more than maxFree free
+// elements, which allows tuning how aggressively memory is reclaimed.
+func (l *List[T]) CompactTo(maxFree int) error {
+	if maxFree < 0 {
+		return ErrInvalidAmount
+	}
+	if l.Free() <= maxFree {
+		return nil
+	}
+	return l.Compact()
+}
+
+// Normalize moves the elements in place so that the back of the list is at
+// the beginning of its underlying slice, which makes all the elements
+// contiguous in memory. It doesn't allocate.
+func (l *List[T]) Normalize() {
+	if l.back == 0 {
+		return
+	}
+
+	if !l.wraps() {
+		selfWrapCopy(l.s, l.back, l.len, -l.back)
+		if !l.NoZero {
+			clear(l.s[max(l.back, l.len) : l.back+l.len])
+		}
+		l.back = 0
+		return
+	}
+
+	// rotate the whole slice, free elements included
+	ll := *l
+	ll.back, ll.len = 0, l.slen
+	ll.reverseRotate(0, ll.len, l.back)
+	l.back = 0
+}
+
+// CompactIfWrapped calls Compact only if the list wraps around its underlying
+// slice. This is a cheap way to make sure that the elements are contiguous.
+func (l *List[T]) CompactIfWrapped() error {
+	if !l.wraps() {
+		return nil
+	}
+	return l.Compact()
+}
+
+// Stride returns a new slice with every step-th element of the list, starting
+// from its back. If step<1, it returns nil.
+func (l *List[T]) Stride(step int) []T {
+	if step < 1 {
+		return nil
+	}
+
+	ret := make([]T, 0, (l.len+step-1)/step)
+	for i := 0; i < l.len; i += step {
+		ret = append(ret, l.s[l.abs(i)])
+	}
+
+	return ret
+}
+
+// Batches splits the list into consecutive new lists of size elements each,
+// except for the last one, which may have less. The new lists do not share
+// memory with the original one. If size<1, it returns nil.
+func (l *List[T]) Batches(size int) []*List[T] {
+	if size < 1 {
+		return nil
+	}
+
+	ret := make([]*List[T], 0, (l.len+size-1)/size)
+	for i := 0; i < l.len; i += size {
+		ret = append(ret, l.copyRange(i, min(size, l.len-i)))
+	}
+
+	return ret
+}
+
+// copyRange returns a new list with a copy of the n elements starting at
+// position i, which are assumed to be valid, and the same customizations of
+// the original list.
+func (l *List[T]) copyRange(i, n int) *List[T] {
+	s := make([]T, n)
+	wrapCopy(l.s, s, l.abs(i), 0, n)
+	return l.newWith(s)
+}
+
+// newWith returns a new list using all the elements of s, and the same
+// customizations of the original list.
+func (l *List[T]) newWith(s []T) *List[T] {
+	ret := New(s, true)
+	ret.AllocFunc = l.AllocFunc
+	ret.FreeFunc = l.FreeFunc
+	ret.StringFunc = l.StringFunc
+	ret.NoZero = l.NoZero
+	ret.MaxCap = l.MaxCap
+	ret.text = l.text
+	ret.binary = l.binary
+
+	return ret
+}
+
+// Clone returns a copy of the list that doesn't share memory with it. The new
+// list will have its back at the beginning of its slice, no free space, and the
+// same customizations.
+func (l *List[T]) Clone() *List[T] { return l.copyRange(0, l.len) }
+
+// EqualFunc returns whether both lists have the same length and eq returns true
+// for each pair of elements at the same position.
+func (l *List[T]) EqualFunc(other *List[T], eq func(a, b T) bool) bool {
+	if l.len != other.len {
+		return false
+	}
+	for i := range l.len {
+		if !eq(l.s[l.abs(i)], other.s[other.abs(i)]) {
+			return false
+		}
+	}
+	return true
+}
+
+// IndexFunc returns the first position i satisfying pred(l.At(i)), or -1 if
+// none do.
+func (l *List[T]) IndexFunc(pred func(T) bool) int {
+	for i := range l.len {
+		if pred(l.s[l.abs(i)]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// LastIndexFunc returns the last position i satisfying pred(l.At(i)), or -1
+// if none do.
+func (l *List[T]) LastIndexFunc(pred func(T) bool) int {
+	for i := l.len - 1; 0 <= i; i-- {
+		if pred(l.s[l.abs(i)]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Segments returns the elements of the list as up to two slices that share
+// memory with the list. The first slice holds the elements starting at the
+// back of the list, and the second one holds the rest of them if the list
+// wraps around its underlying slice, otherwise it is nil. Both are nil if the
+// list is empty. The returned slices should not be retained or used after the
+// list is modified.
+func (l *List[T]) Segments() ([]T, []T) {
+	if l.len == 0 {
+		return nil, nil
+	}
+	if !l.wraps() {
+		end := l.back + l.len
+		return l.s[l.back:end:end], nil
+	}
+	end := l.back + l.len - l.slen
+	return l.s[l.back:l.slen:l.slen], l.s[:end:end]
+}
+
+// ToSlice returns a new slice with the elements of the list.
+func (l *List[T]) ToSlice() []T {
+	return l.AppendTo(make([]T, 0, l.len))
+}
+
+// Head returns a new slice with the first n elements of the list, starting
+// from its back. The value of n is clamped to the range [0, l.Len()].
+func (l *List[T]) Head(n int) []T {
+	n = min(max(n, 0), l.len)
+	ret := make([]T, n)
+	wrapCopy(l.s, ret, l.back, 0, n)
+
+	return ret
+}
+
+// Tail returns a new slice with the last n elements of the list, ending at its
+// front. The value of n is clamped to the range [0, l.Len()].
+func (l *List[T]) Tail(n int) []T {
+	n = min(max(n, 0), l.len)
+	ret := make([]T, n)
+	wrapCopy(l.s, ret, l.back+l.len-n, 0, n)
+
+	return ret
+}
+
+// AppendTo appends the elements of the list to dst and returns the extended
+// slice.
+func (l *List[T]) AppendTo(dst []T) []T {
+	n := len(dst)
+	dst = slices.Grow(dst, l.len)[:n+l.len]
+	wrapCopy(l.s, dst[n:], l.back, 0, l.len)
+
+	return dst
+}
+
+// Any returns whether at least one element satisfies pred. It returns false
+// for an empty list.
+func (l *List[T]) Any(pred func(T) bool) bool { return l.IndexFunc(pred) >= 0 }
+
+// Every returns whether all the elements satisfy pred. It returns true for an
+// empty list.
+func (l *List[T]) Every(pred func(T) bool) bool {
+	return l.IndexFunc(func(v T) bool { return !pred(v) }) < 0
+}
+
+// None returns whether no element satisfies pred. It returns true for an empty
+// list.
+func (l *List[T]) None(pred func(T) bool) bool { return !l.Any(pred) }
+
+// IndicesFunc returns all the positions whose elements satisfy pred, in
+// ascending order.
+func (l *List[T]) IndicesFunc(pred func(T) bool) []int {
+	var ret []int
+	for i := range l.len {
+		if pred(l.s[l.abs(i)]) {
+			ret = append(ret, i)
+		}
+	}
+	return ret
+}
+
+// CountFunc returns the number of elements satisfying pred.
+func (l *List[T]) CountFunc(pred func(T) bool) int {
+	var n int
+	for i := range l.len {
+		if pred(l.s[l.abs(i)]) {
+			n++
+		}
+	}
+	return n
+}
+
+// MinFunc returns the minimal element according to cmp, and true. If there are
+// several minimal elements, the one at the smallest position is returned. If
+// the list is empty, it returns the zero value and false.
+func (l *List[T]) MinFunc(cmp CompareFunc[T]) (T, bool) {
+	ret, ok := l.Val(0)
+	for i := 1; i < l.len; i++ {
+		if v := l.s[l.abs(i)]; cmp(v, ret) < 0 {
+			ret = v
+		}
+	}
+	return ret, ok
+}
+
+// MaxFunc returns the maximal element according to cmp, and true. If there are
+// several maximal elements, the one at the smallest position is returned. If
+// the list is empty, it returns the zero value and false.
+func (l *List[T]) MaxFunc(cmp CompareFunc[T]) (T, bool) {
+	ret, ok := l.Val(0)
+	for i := 1; i < l.len; i++ {
+		if v := l.s[l.abs(i)]; cmp(v, ret) > 0 {
+			ret = v
+		}
+	}
+	return ret, ok
+}
+
+// MinMaxFunc is like calling MinFunc and MaxFunc, but it processes elements in
+// pairs so that it only makes about 3/2 comparisons per element.
+func (l *List[T]) MinMaxFunc(cmp CompareFunc[T]) (min, max T, ok bool) {
+	min, ok = l.Val(0)
+	max = min
+	for i := 1; i < l.len; i += 2 {
+		small := l.s[l.abs(i)]
+		large := small
+		if i+1 < l.len {
+			v := l.s[l.abs(i+1)]
+			if c := cmp(v, small); c < 0 {
+				small = v
+			} else if c > 0 {
+				large = v
+			}
+		}
+		if cmp(small, min) < 0 {
+			min = small
+		}
+		if cmp(large, max) > 0 {
+			max = large
+		}
+	}
+	return min, max, ok
+}
+
 // CopyTo copies at most n elements starting at index i to the given slice, and
 // returns the number of copied elements. If j<i, then it wraps the list.
 func (l *List[T]) CopyTo(s []T, i, n int) error {
@@ -255,10 +666,11 @@ func (l *List[T]) Swap(i, j int) {
 	l.SwapOK(i, j)
 }
 
-// SwapOK swaps the i-eth and j-eth elements. If i==j, it's a nop and returns
+// SwapOK swaps the i-eth and j-eth elements. If either of the elements is out
+// of range, it returns ErrInvalidPosition. If i==j, it's a nop and returns
 // false. Otherwise, it returns true and swaps the elements.
 func (l *List[T]) SwapOK(i, j int) (bool, error) {
-	if l.elBound(i) || l.elBound(j) {
+	if !l.elBound(i) || !l.elBound(j) {
 		return false, ErrInvalidPosition
 	}
 	if i == j {
@@ -271,12 +683,88 @@ func (l *List[T]) SwapOK(i, j int) (bool, error) {
 	return true, nil
 }
 
+// Choice returns an element of the list chosen uniformly at random using the
+// default math/rand.Source, and true. If the list is empty, it returns the
+// zero value and false.
+func (l *List[T]) Choice() (T, bool) { return l.choice(rand.Intn) }
+
+// ChoiceRand is like Choice but allows specifying an alternative
+// *math/rand.Rand.
+func (l *List[T]) ChoiceRand(r *rand.Rand) (T, bool) { return l.choice(r.Intn) }
+
+func (l *List[T]) choice(intn func(int) int) (v T, ok bool) {
+	if l.len == 0 {
+		return v, false
+	}
+	return l.s[l.abs(intn(l.len))], true
+}
+
+// Sample returns a new slice with k distinct elements of the list, chosen
+// uniformly at random using the default math/rand.Source. The list is not
+// modified. It returns ErrInvalidAmount if k is negative or greater than
+// l.Len().
+func (l *List[T]) Sample(k int) ([]T, error) { return l.sample(rand.Intn, k) }
+
+// SampleRand is like Sample but allows specifying an alternative
+// *math/rand.Rand.
+func (l *List[T]) SampleRand(r *rand.Rand, k int) ([]T, error) {
+	return l.sample(r.Intn, k)
+}
+
+// sample runs the first k steps of a Fisher-Yates shuffle over the positions
+// of the list, only keeping track of the positions that were swapped.
+func (l *List[T]) sample(intn func(int) int, k int) ([]T, error) {
+	if k < 0 || l.len < k {
+		return nil, ErrInvalidAmount
+	}
+
+	ret := make([]T, k)
+	swapped := make(map[int]int, k)
+	for i := range k {
+		j := i + intn(l.len-i)
+		pos, ok := swapped[j]
+		if !ok {
+			pos = j
+		}
+		if swapped[j], ok = swapped[i]; !ok {
+			swapped[j] = i
+		}
+		ret[i] = l.s[l.abs(pos)]
+	}
+
+	return ret, nil
+}
+
 // Shuffle pseudo-randomizes the order of elements using the default
 // math/rand.Source.
 func (l *List[T]) Shuffle() {
 	rand.Shuffle(l.len, l.Swap)
 }
 
+// ShuffleSeed is like Shuffle, but uses a new math/rand.Source created with the
+// given seed, so the result is reproducible.
+func (l *List[T]) ShuffleSeed(seed int64) {
+	rand.New(rand.NewSource(seed)).Shuffle(l.len, l.Swap)
+}
+
+// CryptoShuffle randomizes the order of elements using crypto/rand, so the
+// result is unpredictable. It's considerably slower than Shuffle. If reading
+// random data fails, the error is returned and the list will be partially
+// shuffled.
+func (l *List[T]) CryptoShuffle() error { return l.cryptoShuffle(crand.Reader) }
+
+func (l *List[T]) cryptoShuffle(r io.Reader) error {
+	for i := l.len - 1; i > 0; i-- {
+		j, err := crand.Int(r, big.NewInt(int64(i+1)))
+		if err != nil {
+			return fmt.Errorf("crypto shuffle: %w", err)
+		}
+		l.Swap(i, int(j.Int64()))
+	}
+
+	return nil
+}
+
 // ShuffleN is like Shuffle, but allows specifying a specific range to be
 // shuffled.
 func (l *List[T]) ShuffleRange(i, j int) error {
@@ -311,8 +799,181 @@ func (l *List[T]) ShuffleRangeRand(r *rand.Rand, i, j int) error {
 	return nil
 }
 
-// Rotate rotates the list n elements, which can be negative. It is O(1).
-func (l *List[T]) Rotate(n int) { l.back = l.fixAbs(n) }
+// ShuffleRangeSeed is like ShuffleRange, but uses a new math/rand.Source
+// created with the given seed, so the result is reproducible.
+func (l *List[T]) ShuffleRangeSeed(seed int64, i, j int) error {
+	return l.ShuffleRangeRand(rand.New(rand.NewSource(seed)), i, j)
+}
+
+// Reverse reverses the order of the elements in the list, in place.
+func (l *List[T]) Reverse() { l.reverse(0, l.len) }
+
+// ReverseRange is like Reverse, but only reverses the elements in the range
+// [i, j). It is equivalent to ReverseRangeInPlace.
+func (l *List[T]) ReverseRange(i, j int) error {
+	return l.ReverseRangeInPlace(i, j)
+}
+
+// ReverseRangeInPlace reverses the elements in the range [i, j) by swapping
+// them directly in the underlying slice, from both ends of the range towards
+// its middle. It never allocates.
+func (l *List[T]) ReverseRangeInPlace(i, j int) error {
+	if !l.rngBound(i, j) {
+		return ErrInvalidRange
+	}
+	l.reverse(i, j)
+
+	return nil
+}
+
+// reverse reverses the elements in the range [i, j), which is assumed to be
+// valid.
+func (l *List[T]) reverse(i, j int) {
+	if j-i < 2 {
+		return
+	}
+	x, y := l.abs(i), l.abs(j-1)
+	for n := (j - i) / 2; 0 < n; n-- {
+		l.s[x], l.s[y] = l.s[y], l.s[x]
+		if x++; x == l.slen {
+			x = 0
+		}
+		if y == 0 {
+			y = l.slen
+		}
+		y--
+	}
+}
+
+// reverseRotate rotates the elements in the range [i, j) so that the element
+// at position i+n becomes the one at position i. It uses three in-place
+// reversals, unless the shorter of the two parts being exchanged fits in the
+// free space of the list, in which case the free space is used as scratch to
+// move the elements with copies, which is considerably faster. It never
+// allocates. The arguments are assumed to be valid, and n to be in the range
+// [0, j-i).
+func (l *List[T]) reverseRotate(i, j, n int) {
+	if n == 0 {
+		return
+	}
+
+	m, free, scratch := j-i-n, l.Free(), l.back+l.len
+	switch {
+	case n <= m && n <= free:
+		wrapCopy(l.s, l.s, l.abs(i), scratch, n)
+		selfWrapCopy(l.s, l.abs(i+n), m, -n)
+		wrapCopy(l.s, l.s, scratch, l.abs(i+m), n)
+		l.wrapClear(scratch, n)
+
+	case m < n && m <= free:
+		wrapCopy(l.s, l.s, l.abs(i+n), scratch, m)
+		selfWrapCopy(l.s, l.abs(i), n, m)
+		wrapCopy(l.s, l.s, scratch, l.abs(i), m)
+		l.wrapClear(scratch, m)
+
+	default:
+		l.reverse(i, i+n)
+		l.reverse(i+n, j)
+		l.reverse(i, j)
+	}
+}
+
+// Rotate rotates the list n elements, which can be negative, so that the
+// element at position n becomes the back. It is O(1) if the list has no free
+// space. Otherwise, the free space sits between the front and the back of the
+// list, so the elements are moved in place in O(l.Len()).
+func (l *List[T]) Rotate(n int) {
+	n = fix(l.len, n)
+	if n == 0 {
+		return
+	}
+	if l.len == l.slen {
+		l.back = l.abs(n)
+		return
+	}
+	l.reverseRotate(0, l.len, n)
+}
+
+// RotateTo rotates the list so that the element at position i becomes the
+// back. Unlike Rotate, i must be a valid position, otherwise it returns
+// ErrInvalidPosition. It is O(1) if the list has no free space, otherwise the
+// elements are moved in place in O(n).
+func (l *List[T]) RotateTo(i int) error {
+	if !l.elBound(i) {
+		return ErrInvalidPosition
+	}
+	l.Rotate(i)
+
+	return nil
+}
+
+// RotateRange is like Rotate, but only rotates the elements in the range [i,
+// j), so that the element at position i+n becomes the one at position i. The
+// elements are moved in place in O(j-i).
+func (l *List[T]) RotateRange(i, j, n int) error {
+	if !l.rngBound(i, j) {
+		return ErrInvalidRange
+	}
+	l.reverseRotate(i, j, fix(j-i, n))
+
+	return nil
+}
+
+// RotateReportingSplit is like Rotate, but it also returns the position of
+// the first element that is stored at the beginning of the underlying slice,
+// or -1 if the list doesn't wrap after the rotation.
+func (l *List[T]) RotateReportingSplit(n int) (splitIndex int) {
+	l.Rotate(n)
+	if !l.wraps() {
+		return -1
+	}
+	return l.slen - l.back
+}
+
+// MoveRange moves the n elements starting at position i so that they start at
+// position dst, shifting the elements in between to make room for them. The
+// value of dst must be in the range [0, l.Len()-n]. The elements are moved in
+// place in O(|dst-i|+n).
+func (l *List[T]) MoveRange(i, n, dst int) error {
+	if n < 0 || !l.rngBound(i, i+n) || dst < 0 || l.len-n < dst {
+		return ErrInvalidRange
+	}
+	if dst < i {
+		l.reverseRotate(dst, i+n, i-dst)
+	} else if i < dst {
+		l.reverseRotate(i, dst+n, n)
+	}
+
+	return nil
+}
+
+// MoveToFront moves the element at position i to the front of the list,
+// shifting the elements that were in front of it one position towards the
+// back. It doesn't allocate.
+func (l *List[T]) MoveToFront(i int) error {
+	if !l.elBound(i) {
+		return ErrInvalidPosition
+	}
+	v := l.s[l.abs(i)]
+	selfWrapCopy(l.s, l.abs(i)+1, l.len-i-1, -1)
+	l.s[l.abs(l.len-1)] = v
+
+	return nil
+}
+
+// MoveToBack moves the element at position i to the back of the list,
+// shifting the elements that were behind it one position towards the front.
+// It doesn't allocate.
+func (l *List[T]) MoveToBack(i int) error {
+	if !l.elBound(i) {
+		return ErrInvalidPosition
+	}
+	v := l.s[l.abs(i)]
+	selfWrapCopy(l.s, l.back, i, 1)
+	l.s[l.back] = v
+
+	return nil
+}
 
 // Val returns the element at the given position and true, if it exists.
 // Otherwise, it returns the zero value and false.
@@ -323,6 +984,39 @@ func (l *List[T]) Val(i int) (v T, ok bool) {
 	return
 }
 
+// ValWithAbs is like Val, but also returns the position of the element in the
+// underlying slice, or -1 if it doesn't exist. This is mostly useful to debug
+// custom allocation strategies.
+func (l *List[T]) ValWithAbs(i int) (v T, absIndex int, ok bool) {
+	if l.elBound(i) {
+		absIndex = l.abs(i)
+		return l.s[absIndex], absIndex, true
+	}
+	return v, -1, false
+}
+
+// SetAt replaces the element at position i with v.
+func (l *List[T]) SetAt(i int, v T) error {
+	if !l.elBound(i) {
+		return ErrInvalidPosition
+	}
+	l.s[l.abs(i)] = v
+
+	return nil
+}
+
+// Update replaces the element at position i with the result of calling f with
+// it.
+func (l *List[T]) Update(i int, f func(T) T) error {
+	if !l.elBound(i) {
+		return ErrInvalidPosition
+	}
+	i = l.abs(i)
+	l.s[i] = f(l.s[i])
+
+	return nil
+}
+
 // At returns the element at the given position, which can wrap the list from
 // either side. This means that At(-1) is the same as At(l.Len()-1). It returns
 // the zero value if the list is empty.
@@ -341,27 +1035,344 @@ func (l *List[T]) Back() T { return l.At(0) }
 // returns the zero value. It is equivalent to l.At(-1).
 func (l *List[T]) Front() T { return l.At(-1) }
 
+// PeekBack returns the element at the back of the list and true. If the list
+// is empty, it returns the zero value and false.
+func (l *List[T]) PeekBack() (T, bool) { return l.Val(0) }
+
+// PeekFront returns the element at the front of the list and true. If the list
+// is empty, it returns the zero value and false.
+func (l *List[T]) PeekFront() (T, bool) { return l.Val(l.len - 1) }
+
 // Push pushes the given element to the front of the list.
 func (l *List[T]) Push(v T) { l.Replace(l.len, l.len, v) }
 
+// PushOverwrite adds v to the front of the list without allocating. If the
+// list is full, then the element at the back of the list is overwritten and
+// returned along with true, so the list works as a fixed capacity ring buffer.
+// If the list has no capacity at all, then v itself is returned along with
+// true.
+func (l *List[T]) PushOverwrite(v T) (evicted T, didEvict bool) {
+	if l.slen == 0 {
+		return v, true
+	}
+	if l.len < l.slen {
+		l.s[l.abs(l.len)] = v
+		l.len++
+		return evicted, false
+	}
+
+	evicted, l.s[l.back] = l.s[l.back], v
+	l.back = l.abs(1)
+
+	return evicted, true
+}
+
 // Pop removes the element at the front of the list and returns it. If the list
 // is empty, it returns the zero value and does nothing.
 func (l *List[T]) Pop() T {
 	v, ok := l.Val(l.len - 1)
 	if ok {
-		l.Replace(l.len-1, 1)
+		l.Replace(l.len-1, l.len)
 	}
 	return v
 }
 
+// PushN adds all the given elements to the front of the list, in order. It's
+// the same as Append.
+func (l *List[T]) PushN(vs ...T) error { return l.Append(vs...) }
+
+// PopN removes the n elements at the front of the list and returns them in a
+// new slice, in the same order they were in the list. It returns
+// ErrInvalidAmount if n is negative or greater than l.Len().
+func (l *List[T]) PopN(n int) ([]T, error) {
+	if n < 0 || l.len < n {
+		return nil, ErrInvalidAmount
+	}
+	ret := make([]T, n)
+	wrapCopy(l.s, ret, l.back+l.len-n, 0, n)
+	l.wrapClear(l.back+l.len-n, n)
+	l.len -= n
+
+	return ret, nil
+}
+
+// PopBackN is like PopN, but removes the elements from the back of the list.
+func (l *List[T]) PopBackN(n int) ([]T, error) {
+	if n < 0 || l.len < n {
+		return nil, ErrInvalidAmount
+	}
+	ret := make([]T, n)
+	wrapCopy(l.s, ret, l.back, 0, n)
+	l.wrapClear(l.back, n)
+	l.back = fix(l.slen, l.back+n)
+	l.len -= n
+
+	return ret, nil
+}
+
+// SwapRemove removes the element at position i and returns it. The element at
+// the front of the list is moved to position i, so it's O(1) but doesn't
+// preserve the order of the elements.
+func (l *List[T]) SwapRemove(i int) (v T, err error) {
+	if !l.elBound(i) {
+		return v, ErrInvalidPosition
+	}
+
+	i, front := l.abs(i), l.abs(l.len-1)
+	v, l.s[i] = l.s[i], l.s[front]
+	l.wrapClear(front, 1)
+	l.len--
+
+	return v, nil
+}
+
 // Clear removes all the elements in the list and returns the number of
 // elements removed.
 func (l *List[T]) Clear() int {
-	cleared := wrapClear(l.s, l.back, l.len)
+	cleared := l.len
+	l.wrapClear(l.back, l.len)
 	l.back, l.len = 0, 0
 	return cleared
 }
 
+// Release removes all the elements in the list and drops its underlying
+// slice, which is passed to FreeFunc if it's set, so that it can be recycled.
+// The list is still usable afterwards, and will allocate a new slice when
+// elements are added.
+func (l *List[T]) Release() {
+	if l.s != nil {
+		l.free(nil)
+	}
+	l.s, l.slen = nil, 0
+	l.back, l.len = 0, 0
+}
+
+// Fill sets the n elements starting at position i to v. If i+n>l.Len(), then
+// it wraps the list.
+func (l *List[T]) Fill(v T, i, n int) error {
+	if !l.xBound(i, n) {
+		return ErrInvalidRange
+	}
+	for j := range n {
+		l.s[l.abs(fix(l.len, i+j))] = v
+	}
+
+	return nil
+}
+
+// TryApply replaces each element of the list with the result of calling f
+// with it. If f returns an error, all the elements that were already replaced
+// are restored to their original values, and the error is returned.
+func (l *List[T]) TryApply(f func(T) (T, error)) error {
+	orig := make([]T, 0, l.len)
+	for i := range l.len {
+		pos := l.abs(i)
+		v, err := f(l.s[pos])
+		if err != nil {
+			for j, v := range orig {
+				l.s[l.abs(j)] = v
+			}
+			return fmt.Errorf("apply to list element %d: %w", i, err)
+		}
+		orig = append(orig, l.s[pos])
+		l.s[pos] = v
+	}
+
+	return nil
+}
+
+// Filter removes all the elements for which keep returns false, and returns
+// the number of elements removed. The remaining elements keep their relative
+// order and are moved towards the back of the list.
+func (l *List[T]) Filter(keep func(T) bool) int {
+	var kept int
+	for i := range l.len {
+		v := l.s[l.abs(i)]
+		if keep(v) {
+			if kept != i {
+				l.s[l.abs(kept)] = v
+			}
+			kept++
+		}
+	}
+
+	removed := l.len - kept
+	l.wrapClear(l.back+kept, removed)
+	l.len = kept
+
+	return removed
+}
+
+// RemoveFunc removes the elements for which pred returns true, and returns the
+// number of elements removed. It's the opposite of Filter.
+func (l *List[T]) RemoveFunc(pred func(T) bool) int {
+	return l.Filter(func(v T) bool { return !pred(v) })
+}
+
+// RemoveAll removes the elements that are equal to v, as reported by eq, and
+// returns the number of elements removed. For comparable types, Eq can be used
+// as eq.
+func (l *List[T]) RemoveAll(v T, eq func(a, b T) bool) int {
+	return l.Filter(func(x T) bool { return !eq(x, v) })
+}
+
+// Partition rearranges the elements of the list so that all the elements for
+// which pred returns true come before the ones for which it returns false, and
+// returns the position of the first element of the latter group, or l.Len() if
+// there are none. The relative order of the elements is not preserved. It
+// doesn't allocate.
+func (l *List[T]) Partition(pred func(T) bool) int {
+	i, j := 0, l.len
+	for {
+		for i < j && pred(l.s[l.abs(i)]) {
+			i++
+		}
+		for i < j && !pred(l.s[l.abs(j-1)]) {
+			j--
+		}
+		if i == j {
+			return i
+		}
+		a, b := l.abs(i), l.abs(j-1)
+		l.s[a], l.s[b] = l.s[b], l.s[a]
+		i, j = i+1, j-1
+	}
+}
+
+// StablePartition is like Partition, but preserves the relative order of the
+// elements within each group. It allocates a scratch slice to hold the
+// elements for which pred returns false.
+func (l *List[T]) StablePartition(pred func(T) bool) int {
+	var failed []T
+	var passed int
+	for i := range l.len {
+		v := l.s[l.abs(i)]
+		if !pred(v) {
+			failed = append(failed, v)
+			continue
+		}
+		if passed != i {
+			l.s[l.abs(passed)] = v
+		}
+		passed++
+	}
+	wrapCopy(failed, l.s, 0, l.back+passed, len(failed))
+
+	return passed
+}
+
+// DedupFunc removes the elements that are equal to the previous one, as
+// reported by eq, and returns the number of elements removed. The remaining
+// elements keep their relative order and are moved towards the back of the
+// list.
+func (l *List[T]) DedupFunc(eq func(a, b T) bool) int {
+	if l.len < 2 {
+		return 0
+	}
+
+	kept := 1
+	for i := 1; i < l.len; i++ {
+		v := l.s[l.abs(i)]
+		if !eq(l.s[l.abs(kept-1)], v) {
+			if kept != i {
+				l.s[l.abs(kept)] = v
+			}
+			kept++
+		}
+	}
+
+	removed := l.len - kept
+	l.wrapClear(l.back+kept, removed)
+	l.len = kept
+
+	return removed
+}
+
+// Trim removes all the leading elements from the back and all the trailing
+// elements from the front of the list that satisfy pred, and returns the
+// number of elements removed.
+func (l *List[T]) Trim(pred func(T) bool) int {
+	var back, front int
+	for back < l.len && pred(l.s[l.abs(back)]) {
+		back++
+	}
+	for front < l.len-back && pred(l.s[l.abs(l.len-1-front)]) {
+		front++
+	}
+
+	l.wrapClear(l.back, back)
+	l.wrapClear(l.back+l.len-front, front)
+	l.back = fix(l.slen, l.back+back)
+	l.len -= back + front
+
+	return back + front
+}
+
+// Truncate removes elements from the front of the list so that it only keeps
+// the first n elements. It returns ErrInvalidAmount if n is negative or
+// greater than l.Len().
+func (l *List[T]) Truncate(n int) error {
+	if n < 0 || l.len < n {
+		return ErrInvalidAmount
+	}
+	l.wrapClear(l.back+n, l.len-n)
+	l.len = n
+
+	return nil
+}
+
+// Split removes the elements in the range [i, l.Len()) and returns them in a
+// new list that doesn't share memory with l and has the same customizations.
+// The elements in the range [0, i) are kept in l. It returns
+// ErrInvalidPosition if i is not in the range [0, l.Len()].
+func (l *List[T]) Split(i int) (*List[T], error) {
+	if i < 0 || l.len < i {
+		return nil, ErrInvalidPosition
+	}
+	ret := l.copyRange(i, l.len-i)
+	l.wrapClear(l.back+i, l.len-i)
+	l.len = i
+
+	return ret, nil
+}
+
+// Extract removes the n elements starting at position i and returns them in a
+// new list that doesn't share memory with l and has the same customizations.
+// If i+n>l.Len(), then it wraps the list, so the elements at the beginning of
+// the list are extracted last.
+func (l *List[T]) Extract(i, n int) (*List[T], error) {
+	if !l.xBound(i, n) {
+		return nil, ErrInvalidRange
+	}
+
+	first := min(n, l.len-i)
+	s := make([]T, n)
+	wrapCopy(l.s, s, l.abs(i), 0, first)
+	wrapCopy(l.s, s[first:], l.back, 0, n-first)
+
+	// deleting never allocates, so these can't fail
+	l.Replace(i, i+first)
+	l.Replace(0, n-first)
+
+	return l.newWith(s), nil
+}
+
+// Resize truncates the list to n elements, or appends copies of fill to its
+// front until it has n elements.
+func (l *List[T]) Resize(n int, fill T) error {
+	if n <= l.len {
+		return l.Truncate(n)
+	}
+	if err := l.Grow(n - l.len); err != nil {
+		return err
+	}
+	for ; l.len < n; l.len++ {
+		l.s[l.abs(l.len)] = fill
+	}
+
+	return nil
+}
+
 // Insert inserts the given elements at position i.
 func (l *List[T]) Insert(i int, s ...T) error {
 	if !l.rngBound(i, i) {
@@ -374,6 +1385,20 @@ func (l *List[T]) Insert(i int, s ...T) error {
 // Append inserts the given elements in the front.
 func (l *List[T]) Append(s ...T) error { return l.Replace(l.len, l.len, s...) }
 
+// Extend inserts all the elements of other in the front of the list, keeping
+// their order, and growing the list at most once. The other list is not
+// modified, and it can be l itself.
+func (l *List[T]) Extend(other *List[T]) error {
+	n := other.len
+	if err := l.Grow(n); err != nil {
+		return err
+	}
+	wrapCopy(other.s, l.s, other.back, l.back+l.len, n)
+	l.len += n
+
+	return nil
+}
+
 // Delete removes the items in the given range.
 func (l *List[T]) Delete(i, j int) error { return l.Replace(i, j) }
 
@@ -383,13 +1408,15 @@ func (l *List[T]) Replace(i, j int, s ...T) error {
 		return ErrInvalidRange
 	}
 
-	n := min(l.len-i, j-i)
-	if n < 1 && len(s) == 0 {
+	n, ls := j-i, len(s)
+	if n == 0 && ls == 0 {
 		return nil // nothing to delete, nothing to insert
 	}
+	if math.MaxInt-(l.len-n) < ls {
+		return ErrTooLarge
+	}
 
-	ls := len(s)
-	frontEls := l.len - i - n
+	frontEls := l.len - j
 	needCap := i + ls + frontEls
 
 	if l.slen < needCap {
@@ -400,7 +1427,7 @@ func (l *List[T]) Replace(i, j int, s ...T) error {
 		}
 		wrapCopy(l.s, ss, l.back, 0, i)
 		wrapCopy(s, ss, 0, i, ls)
-		wrapCopy(l.s, ss, l.fixAbs(-frontEls), i+ls, frontEls)
+		wrapCopy(l.s, ss, l.abs(j), i+ls, frontEls)
 		l.free(ss)
 		l.back, l.len = 0, needCap
 
@@ -416,15 +1443,15 @@ func (l *List[T]) Replace(i, j int, s ...T) error {
 	if i < frontEls {
 		// less elements to copy on the back
 		selfWrapCopy(l.s, l.back, i, balloonOffset)
-		l.back = l.fixAbs(balloonOffset)
+		l.back = fix(l.slen, l.back+balloonOffset)
 	} else {
 		// less elements to copy on the front
-		selfWrapCopy(l.s, i+n, frontEls, balloonOffset)
-		balloonStart = l.fixAbs(l.len - balloonOffset)
+		selfWrapCopy(l.s, l.abs(j), frontEls, -balloonOffset)
+		balloonStart = l.back + needCap
 	}
 
 	if 0 < balloonOffset {
-		wrapClear(l.s, balloonStart, balloonOffset)
+		l.wrapClear(balloonStart, balloonOffset)
 	}
 
 	wrapCopy(s, l.s, 0, l.abs(i), ls)
@@ -435,51 +1462,124 @@ func (l *List[T]) Replace(i, j int, s ...T) error {
 
 // MarshalJSON marshals the list as a JSON Array.
 func (l List[T]) MarshalJSON() ([]byte, error) {
-	if l.len == 0 {
-		return []byte{'[', ']'}, nil
+	// elements are encoded one by one, since marshaling a []T as a whole would
+	// produce a base64 string instead of an array if T is byte-like
+	var buf bytes.Buffer
+	if err := l.EncodeJSON(&buf); err != nil {
+		return nil, err
 	}
-	var b bytes.Buffer
-	enc := json.NewEncoder(&b)
+	return buf.Bytes(), nil
+}
 
-	b.WriteByte('[')
+// EncodeJSON writes the list to w as a JSON Array, without building the whole
+// JSON in memory. The output is the same as that of MarshalJSON.
+func (l *List[T]) EncodeJSON(w io.Writer) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(trimNewlineWriter{w})
 	for i := range l.len {
 		if 0 < i {
-			b.WriteByte(',')
-		}
-		if err := enc.Encode(l.At(i)); err != nil {
-			return nil, fmt.Errorf("encode list element %d: %w", i, err)
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
 		}
-		// remove the annoying extra newline that Encode adds
-		if lastBytePos := b.Len() - 1; b.Bytes()[lastBytePos] == '\n' {
-			b.Truncate(lastBytePos)
+		// encode a pointer so that elements are treated like in a slice
+		if err := enc.Encode(&l.s[l.abs(i)]); err != nil {
+			return fmt.Errorf("encode list element %d: %w", i, err)
 		}
 	}
-	b.WriteByte(']')
 
-	return b.Bytes(), nil
+	_, err := io.WriteString(w, "]")
+	return err
+}
+
+// trimNewlineWriter removes the newline that json.Encoder adds after each
+// value, which is written along with the value.
+type trimNewlineWriter struct {
+	w io.Writer
+}
+
+func (t trimNewlineWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if 0 < n && p[n-1] == '\n' {
+		p = p[:n-1]
+	}
+	if _, err := t.w.Write(p); err != nil {
+		return 0, err
+	}
+	return n, nil
 }
 
 // UnmarshalJSON clears the list and reads a JSON Array as a list of elements.
+// The underlying slice is reused, and more space is allocated as needed with
+// Grow, so AllocFunc and MaxCap are honored. If the JSON value is null, then
+// the list is left empty. If an error is returned, the list is left empty.
 func (l *List[T]) UnmarshalJSON(b []byte) error {
-	// release the current slice, since json.Unmarshal will make its own
-	// allocation
-	l.back = 0
-	l.free(nil)
-	if err := json.Unmarshal(b, &l.s); err != nil {
-		// if T is or contain a pointer type and some items were decoded before
-		// returning the error, then we need to clear the slice to remove those
+	l.Clear()
+	return l.DecodeAppend(b)
+}
+
+// DecodeAppend reads a JSON Array and appends its elements to the front of
+// the list, keeping its current elements. More space is allocated as needed
+// with Grow. If the JSON value is null, the list is not modified. If the JSON
+// value is not an array, it returns ErrNotJSONArray. If an error is returned,
+// the list is left as it was before the call.
+func (l *List[T]) DecodeAppend(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("decode list: %w", err)
+	}
+	if tok == nil {
+		return decodeJSONEnd(dec) // JSON null
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("decode list: %w: found %v", ErrNotJSONArray, tok)
+	}
+
+	prevLen := l.len
+	if err := l.decodeJSONElements(dec); err != nil {
+		// if T is or contain a pointer type and some items were decoded
+		// before the error, then we need to clear them to remove those
 		// unnecessary references
-		clear(l.s)
+		wrapClear(l.s, l.back+prevLen, l.len-prevLen)
+		l.len = prevLen
 
 		return err
 	}
 
-	l.slen = len(l.s)
-	l.len = l.slen
-	// take advantage of the whole capacity of the slice allocated by
-	// json.Unmarshal
-	l.s = l.s[:cap(l.s)]
+	return nil
+}
+
+// decodeJSONElements appends the elements of a JSON Array whose opening
+// bracket was already read, and then reads until the end of the input.
+func (l *List[T]) decodeJSONElements(dec *json.Decoder) error {
+	for i := 0; dec.More(); i++ {
+		if err := l.Grow(1); err != nil {
+			return err
+		}
+		var zero T
+		v := &l.s[l.abs(l.len)]
+		*v = zero // may not be zero if NoZero is set
+		l.len++
+		if err := dec.Decode(v); err != nil {
+			return fmt.Errorf("decode list element %d: %w", i, err)
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("decode list: %w", err)
+	}
 
+	return decodeJSONEnd(dec)
+}
+
+// decodeJSONEnd makes sure that there is no more data after a JSON value.
+func decodeJSONEnd(dec *json.Decoder) error {
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("decode list: unexpected data after JSON value")
+	}
 	return nil
 }
 
@@ -515,8 +1615,106 @@ func (l *List[T]) StringRange(i, n int) (string, error) {
 // individual elements are printed by setting the StringFunc member of the
 // list.
 func (l *List[T]) String() string {
-	s, _ := l.StringRange(0, l.len)
-	return s
+	return l.StringWith(defaultStringOpts)
+}
+
+// StringOpts controls the output of StringWith.
+type StringOpts struct {
+	// Prefix is written before the first element.
+	Prefix string
+	// Suffix is written after the last element.
+	Suffix string
+	// Separator is written between each pair of elements.
+	Separator string
+}
+
+var defaultStringOpts = StringOpts{
+	Prefix:    "[",
+	Suffix:    "]",
+	Separator: ", ",
+}
+
+// StringWith is like String, but allows customizing the prefix, suffix and
+// separator of the elements. String is equivalent to StringWith with "[", "]"
+// and ", " respectively.
+func (l *List[T]) StringWith(opts StringOpts) string {
+	f := l.StringFunc
+	if f == nil {
+		f = toString[T]
+	}
+
+	var b strings.Builder
+	b.WriteString(opts.Prefix)
+	for i := range l.len {
+		if 0 < i {
+			b.WriteString(opts.Separator)
+		}
+		b.WriteString(f(l.s[l.abs(i)]))
+	}
+	b.WriteString(opts.Suffix)
+
+	return b.String()
+}
+
+// WriteTo writes the same as String to w, without building the whole string
+// in memory, and returns the number of bytes written.
+func (l *List[T]) WriteTo(w io.Writer) (int64, error) {
+	f := l.StringFunc
+	if f == nil {
+		f = toString[T]
+	}
+
+	// buffer small writes, flushing when the buffer is full enough
+	const flushSize = 512
+	var written int64
+	buf := make([]byte, 0, 2*flushSize)
+	flush := func() error {
+		n, err := w.Write(buf)
+		written += int64(n)
+		if err == nil && n < len(buf) {
+			err = io.ErrShortWrite
+		}
+		buf = buf[:0]
+		return err
+	}
+
+	buf = append(buf, defaultStringOpts.Prefix...)
+	for i := range l.len {
+		if 0 < i {
+			buf = append(buf, defaultStringOpts.Separator...)
+		}
+		buf = append(buf, f(l.s[l.abs(i)])...)
+		if flushSize <= len(buf) {
+			if err := flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	buf = append(buf, defaultStringOpts.Suffix...)
+	err := flush()
+
+	return written, err
+}
+
+// Format implements fmt.Formatter. The verbs %v and %s without flags, width
+// or precision print the same as String. Otherwise, each element is formatted
+// with the same verb, flags, width and precision, ignoring StringFunc, so that
+// for example %q quotes string elements and %x hex-encodes numbers.
+func (l *List[T]) Format(f fmt.State, verb rune) {
+	format := fmt.FormatString(f, verb)
+	if format == "%v" || format == "%s" {
+		io.WriteString(f, l.String())
+		return
+	}
+
+	io.WriteString(f, defaultStringOpts.Prefix)
+	for i := range l.len {
+		if 0 < i {
+			io.WriteString(f, defaultStringOpts.Separator)
+		}
+		fmt.Fprintf(f, format, l.s[l.abs(i)])
+	}
+	io.WriteString(f, defaultStringOpts.Suffix)
 }
 
 func toString[T any](v T) string {
@@ -524,32 +1722,31 @@ func toString[T any](v T) string {
 }
 
 // selfWrapCopy copies the elements in [i, i+n) m positions to either left (if
-// m<0) or right (if m>0). If n<1, n>=len(s) or m>=len(s) it's a nop.
+// m<0) or right (if m>0), wrapping the slice if needed. If n<1, m==0 or
+// n+|m|>len(s) it's a nop.
 func selfWrapCopy[S ~[]T, T any](s S, i, n, m int) {
 	l := len(s)
-	if l == 0 || n < 1 || l <= n || l <= m || m == 0 {
+	if n < 1 || m == 0 || l < n+max(m, -m) {
 		return
 	}
 
-	i = fix(l, i)
-	targetI := fix(l, i+m)
-
-	if targetI < i {
+	if m < 0 {
 		// copy left part first
-		wrapCopy(s, s, i, targetI, n)
-	} else {
-		// copy right part first
-		var copied int
-		for j, targetJ := i+n, targetI+n; 0 < n; n -= copied {
-			if targetJ -= copied; targetJ < 1 {
-				targetJ += l
-			}
-			if j -= copied; j < 1 {
-				j += l
-			}
-			copied = min(j, targetJ, n)
-			copy(s[targetJ-copied:], s[j-copied:j])
+		wrapCopy(s, s, i, i+m, n)
+		return
+	}
+
+	// copy right part first
+	for j, targetJ := fix(l, i+n), fix(l, i+n+m); 0 < n; {
+		if j == 0 {
+			j = l
 		}
+		if targetJ == 0 {
+			targetJ = l
+		}
+		copied := min(j, targetJ, n)
+		copy(s[targetJ-copied:targetJ], s[j-copied:j])
+		j, targetJ, n = j-copied, targetJ-copied, n-copied
 	}
 }
 
@@ -567,7 +1764,7 @@ func wrapCopy[S ~[]T, T any](s1, s2 S, i1, i2, n int) int {
 	n = min(n, l1, l2)
 	for left := n; 0 < left; {
 		copied := min(left, l1-i1, l2-i2)
-		copy(s1[i1:i1+copied], s2[i2:i2+copied])
+		copy(s2[i2:i2+copied], s1[i1:i1+copied])
 		i1 = fix(l1, i1+copied)
 		i2 = fix(l2, i2+copied)
 		left -= copied
@@ -575,6 +1772,14 @@ func wrapCopy[S ~[]T, T any](s1, s2 S, i1, i2, n int) int {
 	return n
 }
 
+// wrapClear calls the package level wrapClear on the underlying slice, unless
+// NoZero is set.
+func (l *List[T]) wrapClear(i, n int) {
+	if !l.NoZero {
+		wrapClear(l.s, i, n)
+	}
+}
+
 // wrapClear clears at most n elements with respect to index i, which can wrap
 // the slice either ways. If n<0, it means "the n elements before i". Returns
 // the number of cleared elements.
@@ -594,12 +1799,11 @@ func wrapClear[S ~[]T, T any](s S, i, n int) int {
 		return l
 	}
 
-	for cleared, left := 0, n; 0 < left; {
-		i = fix(l, i+cleared)
-		left -= cleared
+	i = fix(l, i)
+	for left := n; 0 < left; i = 0 {
 		j := min(i+left, l)
 		clear(s[i:j])
-		cleared += j - i
+		left -= j - i
 	}
 
 	return n
@@ -1,11 +1,18 @@
 package list
 
 import (
+	"bytes"
 	"cmp"
+	"encoding/gob"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"math/rand"
 	"slices"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -25,171 +32,2526 @@ func assertState[T any](t *testing.T, l *List[T], free int, values []T) {
 	}
 }
 
+// wrappedList returns a list holding values whose back is at position back of
+// a backing slice that has the given amount of free elements. The elements are
+// laid out so that the list wraps the slice if possible. The back is taken
+// modulo the length of the slice.
+func wrappedList[T any](t *testing.T, back, free int, values ...T) *List[T] {
+	t.Helper()
+
+	s := make([]T, len(values)+free)
+	back = fix(len(s), back)
+	for i, v := range values {
+		s[(back+i)%len(s)] = v
+	}
+	l, err := NewN(s, back, len(values))
+	require.NoError(t, err)
+
+	return l
+}
+
+func TestMake(t *testing.T) {
+	t.Parallel()
+
+	l := Make[int](5)
+	assertState(t, l, 5, nil)
+	l = Make[int](0)
+	assertState(t, l, 0, nil)
+	l = Make[int](-1)
+	assertState(t, l, 0, nil)
+
+	testCases := []struct {
+		length, capacity int
+		expected         []string
+		free             int
+	}{
+		{length: 0, capacity: 0},
+		{length: -1, capacity: -1},
+		{length: 0, capacity: 3, free: 3},
+		{length: 2, capacity: 5, expected: []string{"a", "a"}, free: 3},
+		{length: 3, capacity: 3, expected: []string{"a", "a", "a"}},
+		{length: 3, capacity: 1, expected: []string{"a", "a", "a"}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := MakeN(tc.length, tc.capacity, "a")
+			assertState(t, l, tc.free, tc.expected)
+			require.Equal(t, len(tc.expected)+tc.free, l.Cap())
+		})
+	}
+}
+
 func TestNew(t *testing.T) {
 	t.Parallel()
 	s := []int{1, 2, 3, 4}
 
-	l := New[int](nil, false)
-	assertState(t, l, 0, nil)
+	l := New[int](nil, false)
+	assertState(t, l, 0, nil)
+
+	l = New[int](nil, true)
+	assertState(t, l, 0, nil)
+
+	l = New[int](s, false)
+	assertState(t, l, len(s), nil)
+
+	l = New[int](s, true)
+	assertState(t, l, 0, s)
+}
+
+func TestNewT(t *testing.T) {
+	t.Parallel()
+	s := []int{1, 2, 3, 4, 5}
+
+	testCases := []struct {
+		input  []int
+		back   int
+		length int
+
+		fails    bool
+		expected []int
+	}{
+		{input: nil, back: -1, length: -1, fails: true},
+		{input: nil, back: -1, length: 0, fails: true},
+		{input: nil, back: 0, length: -1, fails: true},
+		{input: nil, back: 0, length: 1, fails: true},
+		{input: nil, back: 1, length: 0, fails: true},
+		{input: nil, back: 1, length: 1, fails: true},
+		{input: s, back: -1, length: 0, fails: true},
+		{input: s, back: 0, length: -1, fails: true},
+		{input: s, back: 0, length: len(s) + 1, fails: true},
+		{input: s, back: len(s), length: 0, fails: true},
+
+		// next index is 12
+		{input: nil, back: 0, length: 0},
+		{input: s, back: 0, length: 0},
+		{input: s, back: 0, length: 1, expected: s[:1]},
+		{input: s, back: 0, length: 3, expected: s[:3]},
+		{input: s, back: 0, length: len(s), expected: s},
+		{input: s, back: 1, length: 1, expected: s[1:2]},
+		{input: s, back: 1, length: 2, expected: s[1:3]},
+		{input: s, back: len(s) - 1, length: 1, expected: s[len(s)-1:]},
+		{input: s, back: 3, length: 4, expected: []int{4, 5, 1, 2}},
+		{input: s, back: 3, length: 5, expected: []int{4, 5, 1, 2, 3}},
+		{input: s, back: 3, length: 5, expected: []int{4, 5, 1, 2, 3}},
+		{input: s, back: 4, length: 0},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l, err := NewN[int](tc.input, tc.back, tc.length)
+			if tc.fails {
+				require.Error(t, err)
+				require.Nil(t, l)
+
+			} else {
+				require.NoError(t, err)
+				assertState(t, l, len(tc.input)-tc.length, tc.expected)
+			}
+		})
+	}
+}
+
+func TestList_JSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		// only fill in output if it differs from input and the test should not
+		// fail, otherwise we expect the same as input
+		input, output string
+		fails         bool
+	}{
+		{input: "", fails: true},
+		{input: ".", fails: true},
+		{input: "1", fails: true},
+		{input: `"a"`, fails: true},
+		{input: `{}`, fails: true},
+
+		{input: "null", output: "[]"},
+		{input: "[]"},
+		{input: "[1]"},
+		{input: "[1, 2, 3, 4, 5, 6, 7, 8, 9]"},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			var l List[int]
+			err := json.Unmarshal([]byte(tc.input), &l)
+			if tc.fails {
+				assert.Error(t, err)
+				assert.Equal(t, 0, l.Len())
+
+			} else {
+				// marshal both pointer non-pointer types should succeed and
+				// produce same output
+				b1, err := json.Marshal(l)
+				require.NoError(t, err)
+				b2, err := json.Marshal(&l)
+				require.NoError(t, err)
+				assert.Equal(t, b1, b2)
+				assert.JSONEq(t, cmp.Or(tc.output, tc.input), string(b1))
+			}
+		})
+	}
+}
+
+func TestList_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	var freed [][]int
+	l := wrappedList(t, 3, 3, 1, 2, 3)
+	l.FreeFunc = func(s []int) { freed = append(freed, s) }
+	s := l.s
+
+	require.NoError(t, json.Unmarshal([]byte(`[4, 5, 6, 7, 8]`), l))
+	assertState(t, l, 1, []int{4, 5, 6, 7, 8})
+	require.Equal(t, &s[0], &l.s[0], "should reuse the slice")
+	require.Empty(t, freed)
+
+	require.NoError(t, json.Unmarshal([]byte(`null`), l))
+	assertState(t, l, 6, nil)
+	require.Equal(t, &s[0], &l.s[0], "should keep the slice")
+
+	require.NoError(t, json.Unmarshal([]byte(`[1, 2, 3, 4, 5, 6, 7]`), l))
+	assertState(t, l, l.Cap()-7, []int{1, 2, 3, 4, 5, 6, 7})
+	require.Equal(t, [][]int{s}, freed)
+	require.Equal(t, []int{0, 0, 0, 0, 0, 0}, s)
+
+	s = l.s
+	require.Error(t, json.Unmarshal([]byte(`[1, 2, "a"]`), l))
+	assertState(t, l, l.Cap(), nil)
+	require.Equal(t, &s[0], &l.s[0], "should keep the slice")
+	require.Equal(t, make([]int, len(s)), s, "should clear decoded elements")
+
+	// elements are zeroed before decoding, even with NoZero
+	type pair struct{ A, B int }
+	p := wrappedList(t, 1, 1, pair{1, 2}, pair{3, 4})
+	p.NoZero = true
+	p.Pop()
+	require.NoError(t, json.Unmarshal([]byte(`[{"A":5},{"B":6},{"A":7}]`), p))
+	assertState(t, p, 0, []pair{{5, 0}, {0, 6}, {7, 0}})
+}
+
+func TestList_UnmarshalJSON_allocs(t *testing.T) {
+	b := []byte(`[1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16]`)
+
+	fresh := testing.AllocsPerRun(100, func() {
+		var l List[int]
+		_ = json.Unmarshal(b, &l)
+	})
+	var l List[int]
+	reused := testing.AllocsPerRun(100, func() {
+		_ = json.Unmarshal(b, &l)
+	})
+	require.Less(t, reused, fresh)
+}
+
+func TestList_DecodeAppend(t *testing.T) {
+	t.Parallel()
+
+	l := wrappedList(t, 2, 1, 1, 2)
+	require.NoError(t, l.DecodeAppend([]byte(`[3, 4, 5]`)))
+	require.NoError(t, l.DecodeAppend([]byte(` [6,7] `)))
+	require.NoError(t, l.DecodeAppend([]byte(`[]`)))
+	require.NoError(t, l.DecodeAppend([]byte(`null`)))
+	assertState(t, l, l.Free(), []int{1, 2, 3, 4, 5, 6, 7})
+
+	testCases := []struct {
+		input string
+		err   error
+	}{
+		{input: ``},
+		{input: `1`, err: ErrNotJSONArray},
+		{input: `"a"`, err: ErrNotJSONArray},
+		{input: `{"a": 1}`, err: ErrNotJSONArray},
+		{input: `[8, 9`},
+		{input: `[8, 9, "a"]`},
+		{input: `[8, 9] [10]`},
+		{input: `[8, 9]]`},
+		{input: `null 1`},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, 2, 1, 1, 2)
+			err := l.DecodeAppend([]byte(tc.input))
+			require.Error(t, err)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			}
+			assertState(t, l, l.Free(), []int{1, 2})
+			for i := l.len; i < l.slen; i++ {
+				require.Zero(t, l.s[l.abs(i)], "free element %d", i)
+			}
+		})
+	}
+
+	err := wrappedList(t, 0, 0, 1).DecodeAppend([]byte(`[8, "a"]`))
+	require.ErrorContains(t, err, "list element 1")
+}
+
+type failingJSON int
+
+func (v failingJSON) MarshalJSON() ([]byte, error) {
+	if v < 0 {
+		return nil, errors.New("negative")
+	}
+	return json.Marshal(int(v))
+}
+
+func TestList_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	for back := range 5 {
+		t.Run(fmt.Sprintf("back=%d", back), func(t *testing.T) {
+			l := wrappedList(t, back, 2, "<a>", `"b"`, "c&d")
+			b, err := l.MarshalJSON()
+			require.NoError(t, err)
+			expected, err := json.Marshal(l.ToSlice())
+			require.NoError(t, err)
+			require.Equal(t, string(expected), string(b))
+
+			nested := wrappedList(t, back, 2,
+				New([]int{1, 2}, true),
+				New[int](nil, false),
+				wrappedList(t, 1, 1, 3, 4),
+			)
+			b, err = json.Marshal(nested)
+			require.NoError(t, err)
+			require.Equal(t, `[[1,2],[],[3,4]]`, string(b))
+
+			// byte slices are marshaled as base64 strings, but lists are not
+			bs := wrappedList[byte](t, back, 2, 4, 5, 1, 2, 3)
+			b, err = json.Marshal(bs)
+			require.NoError(t, err)
+			require.Equal(t, `[4,5,1,2,3]`, string(b))
+			var got List[byte]
+			require.NoError(t, json.Unmarshal(b, &got))
+			require.Equal(t, bs.ToSlice(), got.ToSlice())
+
+			f := wrappedList[failingJSON](t, back, 2, 1, 2, -3, 4)
+			_, err = f.MarshalJSON()
+			require.ErrorContains(t, err, "list element 2")
+		})
+	}
+}
+
+type ptrJSON struct{ V int }
+
+func (p *ptrJSON) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprint("ptr", p.V))
+}
+
+func TestList_EncodeJSON(t *testing.T) {
+	t.Parallel()
+
+	type pair struct {
+		A string
+		B []int `json:",omitempty"`
+	}
+
+	type jsonList interface {
+		json.Marshaler
+		EncodeJSON(io.Writer) error
+	}
+
+	assertEncodeJSON := func(t *testing.T, l jsonList, s any) {
+		t.Helper()
+		expected, err := json.Marshal(s)
+		require.NoError(t, err)
+		b, err := l.MarshalJSON()
+		require.NoError(t, err)
+		require.Equal(t, string(expected), string(b))
+
+		var buf bytes.Buffer
+		require.NoError(t, l.EncodeJSON(&buf))
+		require.Equal(t, string(expected), buf.String())
+	}
+
+	for back := range 4 {
+		t.Run(fmt.Sprintf("back=%d", back), func(t *testing.T) {
+			ints := wrappedList(t, back, 1, 1, -2, 300)
+			assertEncodeJSON(t, ints, ints.ToSlice())
+
+			strs := wrappedList(t, back, 1, "<a>", "b\n", `"c"`)
+			assertEncodeJSON(t, strs, strs.ToSlice())
+
+			pairs := wrappedList(t, back, 1, pair{A: "x"}, pair{B: []int{1}}, pair{})
+			assertEncodeJSON(t, pairs, pairs.ToSlice())
+
+			ptrs := wrappedList(t, back, 1, ptrJSON{1}, ptrJSON{2}, ptrJSON{3})
+			assertEncodeJSON(t, ptrs, ptrs.ToSlice())
+
+			nested := wrappedList(t, back, 1, New([]int{1}, true), wrappedList(t, 1, 1, 2, 3))
+			assertEncodeJSON(t, nested, []any{[]int{1}, []int{2, 3}})
+
+			type octet uint8
+			octets := wrappedList[octet](t, back, 1, 4, 5, 1, 2, 3)
+			assertEncodeJSON(t, octets, []int{4, 5, 1, 2, 3})
+		})
+	}
+
+	assertEncodeJSON(t, New[int](nil, false), []int{})
+
+	f := wrappedList[failingJSON](t, 1, 1, 1, -2, 3)
+	var buf bytes.Buffer
+	require.ErrorContains(t, f.EncodeJSON(&buf), "list element 1")
+}
+
+type failingWriter struct{ left int }
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	if f.left < len(p) {
+		n := f.left
+		f.left = 0
+		return n, errors.New("write failed")
+	}
+	f.left -= len(p)
+	return len(p), nil
+}
+
+// shortWriter writes at most one byte at a time and never fails.
+type shortWriter struct{}
+
+func (shortWriter) Write(p []byte) (int, error) {
+	return min(1, len(p)), nil
+}
+
+func TestList_WriteTo(t *testing.T) {
+	t.Parallel()
+
+	testCases := [][]int{
+		{},
+		{1},
+		{1, 2, 3, 4, 5},
+		make([]int, 1000),
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, len(tc)/2+1, 2, tc...)
+			var b bytes.Buffer
+			n, err := l.WriteTo(&b)
+			require.NoError(t, err)
+			require.Equal(t, l.String(), b.String())
+			require.Equal(t, int64(b.Len()), n)
+
+			l.StringFunc = func(v int) string { return fmt.Sprintf("<%d>", v) }
+			b.Reset()
+			n, err = l.WriteTo(&b)
+			require.NoError(t, err)
+			require.Equal(t, l.String(), b.String())
+			require.Equal(t, int64(b.Len()), n)
+		})
+	}
+
+	require.Equal(t, "[]", New[int](nil, false).String())
+
+	l := New(make([]int, 1000), true)
+	n, err := l.WriteTo(&failingWriter{left: 700})
+	require.Error(t, err)
+	require.Equal(t, int64(700), n)
+
+	n, err = l.WriteTo(shortWriter{})
+	require.ErrorIs(t, err, io.ErrShortWrite)
+	require.Equal(t, int64(1), n)
+}
+
+func TestList_StringWith(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input    []int
+		opts     StringOpts
+		expected string
+	}{
+		{input: []int{}, opts: StringOpts{Prefix: "|", Suffix: "|", Separator: "|"}, expected: "||"},
+		{input: []int{1}, opts: StringOpts{Prefix: "|", Suffix: "|", Separator: "|"}, expected: "|1|"},
+		{input: []int{1, 2, 3}, opts: StringOpts{Prefix: "|", Suffix: "|", Separator: "|"}, expected: "|1|2|3|"},
+		{input: []int{1, 2, 3}, opts: StringOpts{Separator: " "}, expected: "1 2 3"},
+		{input: []int{1, 2, 3}, opts: StringOpts{Separator: "\n"}, expected: "1\n2\n3"},
+		{input: []int{1, 2, 3}, expected: "123"},
+		{input: []int{1, 2, 3}, opts: defaultStringOpts, expected: "[1, 2, 3]"},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, 2, 1, tc.input...)
+			require.Equal(t, tc.expected, l.StringWith(tc.opts))
+		})
+	}
+
+	l := wrappedList(t, 2, 1, 1, 2)
+	l.StringFunc = func(v int) string { return fmt.Sprintf("%02d", v) }
+	require.Equal(t, "| 01 | 02 |", l.StringWith(StringOpts{"| ", " |", " | "}))
+}
+
+func TestList_Format(t *testing.T) {
+	t.Parallel()
+
+	strs := wrappedList(t, 2, 1, "a", `b"c`, "d")
+	ints := wrappedList(t, 2, 1, 10, 255, -3)
+	floats := wrappedList(t, 2, 1, 1.5, 2.25)
+	ints.StringFunc = func(v int) string { return fmt.Sprint("<", v, ">") }
+
+	testCases := []struct {
+		format   string
+		v        any
+		expected string
+	}{
+		{format: "%v", v: strs, expected: "[a, b\"c, d]"},
+		{format: "%s", v: strs, expected: "[a, b\"c, d]"},
+		{format: "%q", v: strs, expected: `["a", "b\"c", "d"]`},
+		{format: "%3s", v: strs, expected: `[  a, b"c,   d]`},
+		{format: "%-2q|", v: strs, expected: `["a", "b\"c", "d"]|`},
+		{format: "%x", v: strs, expected: "[61, 622263, 64]"},
+
+		{format: "%v", v: ints, expected: "[<10>, <255>, <-3>]"},
+		{format: "%d", v: ints, expected: "[10, 255, -3]"},
+		{format: "%x", v: ints, expected: "[a, ff, -3]"},
+		{format: "%04X", v: ints, expected: "[000A, 00FF, -003]"},
+		{format: "%+d", v: ints, expected: "[+10, +255, -3]"},
+
+		{format: "%.1f", v: floats, expected: "[1.5, 2.2]"},
+		{format: "%6.2f", v: floats, expected: "[  1.50,   2.25]"},
+
+		{format: "%q", v: New[string](nil, false), expected: "[]"},
+		{format: "%v", v: New[string](nil, false), expected: "[]"},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			require.Equal(t, tc.expected, fmt.Sprintf(tc.format, tc.v))
+		})
+	}
+}
+
+func TestList_StringRange(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5}
+	l := New(testData, true)
+
+	testCases := []struct {
+		i, n     int
+		expected string
+		err      error
+	}{
+		{i: -1, n: 0, err: ErrInvalidRange},
+		{i: 0, n: -1, err: ErrInvalidRange},
+		{i: l.Len(), n: 0, err: ErrInvalidRange},
+		{i: 0, n: l.Len() + 1, err: ErrInvalidRange},
+
+		{i: 0, n: 0, expected: "[]"},
+		{i: 0, n: l.Len(), expected: "[1, 2, 3, 4, 5]"},
+		{i: l.Len() - 1, n: l.Len(), expected: "[5, 1, 2, 3, 4]"},
+		{i: 3, n: 1, expected: "[4]"},
+		{i: 3, n: 0, expected: "[]"},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			result, err := l.StringRange(tc.i, tc.n)
+			if tc.err != nil {
+				require.Empty(t, result)
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestList_SwapOK(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5}
+
+	testCases := []struct {
+		i, j     int
+		swapped  bool
+		err      error
+		expected []int
+	}{
+		{i: -1, j: 0, err: ErrInvalidPosition},
+		{i: 0, j: -1, err: ErrInvalidPosition},
+		{i: 0, j: 5, err: ErrInvalidPosition},
+		{i: 5, j: 0, err: ErrInvalidPosition},
+
+		{i: 0, j: 0, expected: []int{1, 2, 3, 4, 5}},
+		{i: 0, j: 4, swapped: true, expected: []int{5, 2, 3, 4, 1}},
+		{i: 3, j: 1, swapped: true, expected: []int{1, 4, 3, 2, 5}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := New(slices.Clone(testData), true)
+			swapped, err := l.SwapOK(tc.i, tc.j)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				assertState(t, l, 0, testData)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.swapped, swapped)
+				assertState(t, l, 0, tc.expected)
+			}
+		})
+	}
+}
+
+func TestList_Shuffle(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	l := New(slices.Clone(testData), true)
+	l.Shuffle()
+
+	got := make([]int, 0, l.Len())
+	for i := range l.Len() {
+		v, _ := l.Val(i)
+		got = append(got, v)
+	}
+	slices.Sort(got)
+	require.Equal(t, testData, got)
+}
+
+func TestList_ShuffleSeed(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+
+	l1 := wrappedList(t, 5, 2, testData...)
+	l2 := wrappedList(t, 0, 0, testData...)
+	l1.ShuffleSeed(42)
+	l2.ShuffleSeed(42)
+	require.Equal(t, l1.ToSlice(), l2.ToSlice())
+	require.NotEqual(t, testData, l1.ToSlice())
+	got := l1.ToSlice()
+	slices.Sort(got)
+	require.Equal(t, testData, got)
+
+	l1 = wrappedList(t, 5, 2, testData...)
+	l2 = wrappedList(t, 0, 0, testData...)
+	require.ErrorIs(t, l1.ShuffleRangeSeed(42, 3, 10), ErrInvalidRange)
+	require.NoError(t, l1.ShuffleRangeSeed(42, 2, 8))
+	require.NoError(t, l2.ShuffleRangeSeed(42, 2, 8))
+	require.Equal(t, l1.ToSlice(), l2.ToSlice())
+	require.Equal(t, []int{1, 2}, l1.Head(2))
+	require.Equal(t, []int{9}, l1.Tail(1))
+}
+
+func TestList_CryptoShuffle(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	l := wrappedList(t, 5, 2, testData...)
+	require.NoError(t, l.CryptoShuffle())
+	got := l.ToSlice()
+	slices.Sort(got)
+	require.Equal(t, testData, got)
+	require.Equal(t, 2, l.Free())
+
+	require.NoError(t, New[int](nil, false).CryptoShuffle())
+
+	errTest := errors.New("test error")
+	err := l.cryptoShuffle(iotest.ErrReader(errTest))
+	require.ErrorIs(t, err, errTest)
+}
+
+func TestList_Choice(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+
+	l := New[int](nil, false)
+	v, ok := l.Choice()
+	require.False(t, ok)
+	require.Zero(t, v)
+	v, ok = l.ChoiceRand(r)
+	require.False(t, ok)
+	require.Zero(t, v)
+
+	testData := []int{1, 2, 3, 4, 5}
+	l = wrappedList(t, 3, 2, testData...)
+	seen := map[int]bool{}
+	for range 200 {
+		v, ok := l.Choice()
+		require.True(t, ok)
+		require.Contains(t, testData, v)
+		v, ok = l.ChoiceRand(r)
+		require.True(t, ok)
+		require.Contains(t, testData, v)
+		seen[v] = true
+	}
+	require.Len(t, seen, len(testData))
+	assertState(t, l, 2, testData)
+}
+
+func TestList_Sample(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	l := wrappedList(t, 7, 2, testData...)
+	r := rand.New(rand.NewSource(1))
+
+	for _, k := range []int{-1, 11} {
+		got, err := l.Sample(k)
+		require.ErrorIs(t, err, ErrInvalidAmount)
+		require.Nil(t, got)
+		got, err = l.SampleRand(r, k)
+		require.ErrorIs(t, err, ErrInvalidAmount)
+		require.Nil(t, got)
+	}
+
+	got, err := l.Sample(0)
+	require.NoError(t, err)
+	require.Empty(t, got)
+
+	got, err = l.Sample(10)
+	require.NoError(t, err)
+	slices.Sort(got)
+	require.Equal(t, testData, got)
+
+	const iterations, k = 30000, 3
+	counts := make([]int, len(testData))
+	for range iterations {
+		got, err := l.SampleRand(r, k)
+		require.NoError(t, err)
+		require.Len(t, got, k)
+		require.False(t, HasDuplicates(New(got, true)), "sample: %v", got)
+		for _, v := range got {
+			counts[v]++
+		}
+	}
+	expected := float64(iterations*k) / float64(len(testData))
+	for v, count := range counts {
+		require.InEpsilon(t, expected, float64(count), 0.05, "element %d", v)
+	}
+
+	assertState(t, l, 2, testData)
+}
+
+func TestList_ReverseRange(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5}
+
+	testCases := []struct {
+		back, free int
+		i, j       int
+		err        error
+		expected   []int
+	}{
+		{i: -1, j: 2, err: ErrInvalidRange},
+		{i: 2, j: 1, err: ErrInvalidRange},
+		{i: 0, j: 6, err: ErrInvalidRange},
+
+		{i: 0, j: 0, expected: []int{1, 2, 3, 4, 5}},
+		{i: 2, j: 3, expected: []int{1, 2, 3, 4, 5}},
+		{i: 0, j: 5, expected: []int{5, 4, 3, 2, 1}},
+		{i: 1, j: 4, expected: []int{1, 4, 3, 2, 5}},
+		{i: 3, j: 5, expected: []int{1, 2, 3, 5, 4}},
+		{back: 3, i: 0, j: 5, expected: []int{5, 4, 3, 2, 1}},
+		{back: 3, i: 1, j: 4, expected: []int{1, 4, 3, 2, 5}},
+		{back: 4, free: 2, i: 0, j: 5, expected: []int{5, 4, 3, 2, 1}},
+		{back: 4, free: 2, i: 1, j: 5, expected: []int{1, 5, 4, 3, 2}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, tc.back, tc.free, testData...)
+			err := l.ReverseRange(tc.i, tc.j)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				assertState(t, l, tc.free, testData)
+			} else {
+				require.NoError(t, err)
+				assertState(t, l, tc.free, tc.expected)
+			}
+		})
+	}
+
+	l := wrappedList(t, 4, 2, testData...)
+	l.Reverse()
+	assertState(t, l, 2, []int{5, 4, 3, 2, 1})
+}
+
+func TestList_ReverseRangeInPlace(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5, 6, 7}
+
+	testCases := []struct {
+		back, i, j int
+		err        error
+		expected   []int
+	}{
+		{i: 0, j: 8, err: ErrInvalidRange},
+		{i: 4, j: 3, err: ErrInvalidRange},
+
+		{back: 6, i: 0, j: 2, expected: []int{2, 1, 3, 4, 5, 6, 7}},
+		{back: 6, i: 0, j: 7, expected: []int{7, 6, 5, 4, 3, 2, 1}},
+		{back: 4, i: 1, j: 6, expected: []int{1, 6, 5, 4, 3, 2, 7}},
+		{back: 4, i: 3, j: 5, expected: []int{1, 2, 3, 5, 4, 6, 7}},
+		{back: 4, i: 4, j: 7, expected: []int{1, 2, 3, 4, 7, 6, 5}},
+		{back: 1, i: 2, j: 6, expected: []int{1, 2, 6, 5, 4, 3, 7}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, tc.back, 1, testData...)
+			err := l.ReverseRangeInPlace(tc.i, tc.j)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				assertState(t, l, 1, testData)
+			} else {
+				require.NoError(t, err)
+				assertState(t, l, 1, tc.expected)
+			}
+		})
+	}
+}
+
+func TestList_ReverseRangeInPlace_allocs(t *testing.T) {
+	l := wrappedList(t, 4, 1, 1, 2, 3, 4, 5, 6, 7)
+	allocs := testing.AllocsPerRun(10, func() {
+		_ = l.ReverseRangeInPlace(1, 6)
+	})
+	require.Zero(t, allocs)
+}
+
+func TestList_Stride(t *testing.T) {
+	t.Parallel()
+
+	l := wrappedList(t, 6, 2, 1, 2, 3, 4, 5, 6)
+
+	testCases := []struct {
+		step     int
+		expected []int
+	}{
+		{step: -1},
+		{step: 0},
+		{step: 1, expected: []int{1, 2, 3, 4, 5, 6}},
+		{step: 2, expected: []int{1, 3, 5}},
+		{step: 3, expected: []int{1, 4}},
+		{step: 4, expected: []int{1, 5}},
+		{step: 6, expected: []int{1}},
+		{step: 7, expected: []int{1}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			require.Equal(t, tc.expected, l.Stride(tc.step))
+		})
+	}
+}
+
+func TestList_Batches(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5, 6, 7}
+
+	testCases := []struct {
+		size     int
+		expected [][]int
+	}{
+		{size: -1},
+		{size: 0},
+		{size: 1, expected: [][]int{{1}, {2}, {3}, {4}, {5}, {6}, {7}}},
+		{size: 3, expected: [][]int{{1, 2, 3}, {4, 5, 6}, {7}}},
+		{size: 7, expected: [][]int{{1, 2, 3, 4, 5, 6, 7}}},
+		{size: 10, expected: [][]int{{1, 2, 3, 4, 5, 6, 7}}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, 5, 1, testData...)
+			batches := l.Batches(tc.size)
+			require.Len(t, batches, len(tc.expected))
+
+			var all []int
+			for i, b := range batches {
+				assertState(t, b, 0, tc.expected[i])
+				all = append(all, tc.expected[i]...)
+			}
+			if tc.expected != nil {
+				require.Equal(t, testData, all)
+			}
+
+			// batches should be independent from the original list
+			for _, b := range batches {
+				b.Reverse()
+			}
+			assertState(t, l, 1, testData)
+		})
+	}
+}
+
+func TestList_Replace(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5}
+
+	testCases := []struct {
+		back, free int
+		i, j       int
+		s          []int
+		err        error
+		expected   []int
+	}{
+		{i: -1, j: 0, err: ErrInvalidRange},
+		{i: 1, j: 0, err: ErrInvalidRange},
+		{i: 0, j: 6, err: ErrInvalidRange},
+
+		{i: 0, j: 0, expected: []int{1, 2, 3, 4, 5}},
+		{i: 2, j: 2, expected: []int{1, 2, 3, 4, 5}},
+		{i: 1, j: 3, s: []int{8, 9}, expected: []int{1, 8, 9, 4, 5}},
+
+		// delete
+		{back: 3, free: 3, i: 0, j: 1, expected: []int{2, 3, 4, 5}},
+		{back: 3, free: 3, i: 1, j: 2, expected: []int{1, 3, 4, 5}},
+		{back: 3, free: 3, i: 3, j: 4, expected: []int{1, 2, 3, 5}},
+		{back: 3, free: 3, i: 4, j: 5, expected: []int{1, 2, 3, 4}},
+		{back: 3, free: 3, i: 0, j: 5, expected: []int{}},
+		{back: 6, free: 3, i: 1, j: 4, expected: []int{1, 5}},
+
+		// insert
+		{back: 3, free: 3, i: 0, j: 0, s: []int{8, 9}, expected: []int{8, 9, 1, 2, 3, 4, 5}},
+		{back: 3, free: 3, i: 1, j: 1, s: []int{8, 9}, expected: []int{1, 8, 9, 2, 3, 4, 5}},
+		{back: 3, free: 3, i: 4, j: 4, s: []int{8, 9}, expected: []int{1, 2, 3, 4, 8, 9, 5}},
+		{back: 3, free: 3, i: 5, j: 5, s: []int{8, 9}, expected: []int{1, 2, 3, 4, 5, 8, 9}},
+		{back: 7, free: 3, i: 2, j: 2, s: []int{7, 8, 9}, expected: []int{1, 2, 7, 8, 9, 3, 4, 5}},
+
+		// replace with more or less elements
+		{back: 3, free: 3, i: 1, j: 2, s: []int{8, 9}, expected: []int{1, 8, 9, 3, 4, 5}},
+		{back: 3, free: 3, i: 3, j: 4, s: []int{8, 9}, expected: []int{1, 2, 3, 8, 9, 5}},
+		{back: 3, free: 3, i: 1, j: 3, s: []int{9}, expected: []int{1, 9, 4, 5}},
+		{back: 3, free: 3, i: 2, j: 4, s: []int{9}, expected: []int{1, 2, 9, 5}},
+
+		// allocate a new slice
+		{back: 3, free: 1, i: 1, j: 2, s: []int{7, 8, 9}, expected: []int{1, 7, 8, 9, 3, 4, 5}},
+		{back: 3, i: 5, j: 5, s: []int{9}, expected: []int{1, 2, 3, 4, 5, 9}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, tc.back, tc.free, testData...)
+			err := l.Replace(tc.i, tc.j, tc.s...)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				assertState(t, l, tc.free, testData)
+				return
+			}
+
+			require.NoError(t, err)
+			assertState(t, l, l.Cap()-len(tc.expected), tc.expected)
+
+			// free space should always be zeroed
+			for i := l.len; i < l.slen; i++ {
+				require.Zero(t, l.s[l.abs(i)], "free element %d", i)
+			}
+		})
+	}
+}
+
+func TestList_Clone(t *testing.T) {
+	t.Parallel()
+
+	l := wrappedList(t, 3, 2, 1, 2, 3, 4, 5)
+	l.StringFunc = func(v int) string { return fmt.Sprintf("#%d", v) }
+	c := l.Clone()
+	assertState(t, c, 0, []int{1, 2, 3, 4, 5})
+	require.Equal(t, 0, c.back)
+	require.NotNil(t, c.StringFunc)
+
+	require.NoError(t, l.Replace(1, 3, 9))
+	assertState(t, l, 3, []int{1, 9, 4, 5})
+	assertState(t, c, 0, []int{1, 2, 3, 4, 5})
+}
+
+func TestList_EqualFunc(t *testing.T) {
+	t.Parallel()
+
+	eq := func(a, b int) bool { return a == b }
+
+	testCases := []struct {
+		back1, back2 int
+		v1, v2       []int
+		expected     bool
+	}{
+		{expected: true},
+		{v1: []int{1, 2, 3}, v2: []int{1, 2, 3}, expected: true},
+		{back1: 2, v1: []int{1, 2, 3}, v2: []int{1, 2, 3}, expected: true},
+		{back1: 2, back2: 3, v1: []int{1, 2, 3}, v2: []int{1, 2, 3}, expected: true},
+
+		{v1: []int{1, 2, 3}},
+		{v1: []int{1, 2, 3}, v2: []int{1, 2}},
+		{back1: 2, v1: []int{1, 2, 3}, v2: []int{1, 2, 4}},
+		{back2: 3, v1: []int{1, 2, 3}, v2: []int{3, 2, 1}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l1 := wrappedList(t, tc.back1, 2, tc.v1...)
+			l2 := wrappedList(t, tc.back2, 2, tc.v2...)
+			require.Equal(t, tc.expected, l1.EqualFunc(l2, eq))
+			require.Equal(t, tc.expected, l2.EqualFunc(l1, eq))
+		})
+	}
+}
+
+func TestList_Rotate(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5}
+
+	testCases := []struct {
+		back, free, n int
+		expected      []int
+	}{
+		{n: 0, expected: []int{1, 2, 3, 4, 5}},
+		{n: 1, expected: []int{2, 3, 4, 5, 1}},
+		{n: -1, expected: []int{5, 1, 2, 3, 4}},
+		{back: 3, n: 2, expected: []int{3, 4, 5, 1, 2}},
+		{free: 2, n: 2, expected: []int{3, 4, 5, 1, 2}},
+		{free: 2, n: 5, expected: []int{1, 2, 3, 4, 5}},
+		{back: 4, free: 2, n: 1, expected: []int{2, 3, 4, 5, 1}},
+		{back: 6, free: 2, n: -2, expected: []int{4, 5, 1, 2, 3}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, tc.back, tc.free, testData...)
+			l.Rotate(tc.n)
+			assertState(t, l, tc.free, tc.expected)
+			for i := l.len; i < l.slen; i++ {
+				require.Zero(t, l.s[l.abs(i)], "free element %d", i)
+			}
+		})
+	}
+}
+
+func TestList_RotateTo(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5}
+
+	for back := range len(testData) + 2 {
+		for _, free := range []int{0, 2} {
+			l := wrappedList(t, back, free, testData...)
+			require.ErrorIs(t, l.RotateTo(-1), ErrInvalidPosition)
+			require.ErrorIs(t, l.RotateTo(5), ErrInvalidPosition)
+			assertState(t, l, free, testData)
+
+			for i := range testData {
+				l := wrappedList(t, back, free, testData...)
+				require.NoError(t, l.RotateTo(i))
+				require.Equal(t, testData[i], l.Back())
+				expected := append(slices.Clone(testData[i:]), testData[:i]...)
+				assertState(t, l, free, expected)
+			}
+		}
+	}
+}
+
+func TestList_RotateReportingSplit(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5}
+
+	testCases := []struct {
+		back, free, n int
+		expected      []int
+	}{
+		{n: 0, expected: []int{1, 2, 3, 4, 5}},
+		{n: 5, expected: []int{1, 2, 3, 4, 5}},
+		{n: 1, expected: []int{2, 3, 4, 5, 1}},
+		{n: -1, expected: []int{5, 1, 2, 3, 4}},
+		{n: 13, expected: []int{4, 5, 1, 2, 3}},
+		{back: 3, n: 2, expected: []int{3, 4, 5, 1, 2}},
+		{back: 3, n: 3, expected: []int{4, 5, 1, 2, 3}},
+		{free: 2, n: 2, expected: []int{3, 4, 5, 1, 2}},
+		{back: 4, free: 2, n: 1, expected: []int{2, 3, 4, 5, 1}},
+		{back: 6, free: 2, n: -2, expected: []int{4, 5, 1, 2, 3}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, tc.back, tc.free, testData...)
+			split := l.RotateReportingSplit(tc.n)
+			assertState(t, l, tc.free, tc.expected)
+
+			first, second := l.Segments()
+			if split < 0 {
+				require.False(t, l.wraps())
+				require.Nil(t, second)
+				return
+			}
+			require.True(t, l.wraps())
+			require.Len(t, first, split)
+			require.Len(t, second, l.Len()-split)
+			require.Equal(t, 0, l.abs(split))
+		})
+	}
+}
+
+func TestList_IndexFunc(t *testing.T) {
+	t.Parallel()
+
+	l := wrappedList(t, 4, 2, 1, 2, 3, 4, 3, 2, 1)
+
+	testCases := []struct {
+		v, first, last int
+	}{
+		{v: 0, first: -1, last: -1},
+		{v: 1, first: 0, last: 6},
+		{v: 2, first: 1, last: 5},
+		{v: 4, first: 3, last: 3},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			pred := func(v int) bool { return v == tc.v }
+			require.Equal(t, tc.first, l.IndexFunc(pred))
+			require.Equal(t, tc.last, l.LastIndexFunc(pred))
+		})
+	}
+}
+
+func TestList_ValWithAbs(t *testing.T) {
+	t.Parallel()
+
+	l := wrappedList(t, 3, 1, 1, 2, 3, 4)
+
+	testCases := []struct {
+		i, v, absIndex int
+		ok             bool
+	}{
+		{i: -1, absIndex: -1},
+		{i: 4, absIndex: -1},
+
+		{i: 0, v: 1, absIndex: 3, ok: true},
+		{i: 1, v: 2, absIndex: 4, ok: true},
+		{i: 2, v: 3, absIndex: 0, ok: true},
+		{i: 3, v: 4, absIndex: 1, ok: true},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			v, absIndex, ok := l.ValWithAbs(tc.i)
+			require.Equal(t, tc.v, v)
+			require.Equal(t, tc.absIndex, absIndex)
+			require.Equal(t, tc.ok, ok)
+			if ok {
+				require.Equal(t, l.abs(tc.i), absIndex)
+			}
+		})
+	}
+}
+
+func TestList_SetAt(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4}
+
+	testCases := []struct {
+		i        int
+		err      error
+		expected []int
+	}{
+		{i: -1, err: ErrInvalidPosition},
+		{i: 4, err: ErrInvalidPosition},
+
+		{i: 0, expected: []int{9, 2, 3, 4}},
+		{i: 1, expected: []int{1, 9, 3, 4}},
+		{i: 3, expected: []int{1, 2, 3, 9}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, 4, 1, testData...)
+			err := l.SetAt(tc.i, 9)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				assertState(t, l, 1, testData)
+			} else {
+				require.NoError(t, err)
+				assertState(t, l, 1, tc.expected)
+			}
+
+			l = wrappedList(t, 4, 1, testData...)
+			err = l.Update(tc.i, func(v int) int { return v + 8 - tc.i })
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				assertState(t, l, 1, testData)
+			} else {
+				require.NoError(t, err)
+				assertState(t, l, 1, tc.expected)
+			}
+		})
+	}
+
+	// back of the list is at the last position of the slice
+	l := wrappedList(t, 4, 1, testData...)
+	require.NoError(t, l.SetAt(0, 9))
+	require.Equal(t, []int{2, 3, 4, 0, 9}, l.s)
+}
+
+func TestList_Trim(t *testing.T) {
+	t.Parallel()
+
+	isNegative := func(v int) bool { return v < 0 }
+
+	testCases := []struct {
+		input, expected []int
+	}{
+		{input: nil, expected: nil},
+		{input: []int{-1, -1, -1}, expected: nil},
+		{input: []int{1, 2, 3}, expected: []int{1, 2, 3}},
+		{input: []int{1, -1, -1, 3}, expected: []int{1, -1, -1, 3}},
+		{input: []int{-1, -1, 1, -1, 3, -1}, expected: []int{1, -1, 3}},
+		{input: []int{-1, 1, 2}, expected: []int{1, 2}},
+		{input: []int{1, 2, -1}, expected: []int{1, 2}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			for back := range len(tc.input) + 2 {
+				l := wrappedList(t, back, 2, tc.input...)
+				removed := l.Trim(isNegative)
+				require.Equal(t, len(tc.input)-len(tc.expected), removed)
+				assertState(t, l, l.Cap()-len(tc.expected), tc.expected)
+				for i := l.len; i < l.slen; i++ {
+					require.Zero(t, l.s[l.abs(i)], "free element %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestList_Split(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5}
+
+	testCases := []struct {
+		i   int
+		err error
+	}{
+		{i: -1, err: ErrInvalidPosition},
+		{i: 6, err: ErrInvalidPosition},
+
+		{i: 0},
+		{i: 2},
+		{i: 4},
+		{i: 5},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, 4, 2, testData...)
+			l.StringFunc = func(v int) string { return fmt.Sprintf("#%d", v) }
+
+			front, err := l.Split(tc.i)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				require.Nil(t, front)
+				assertState(t, l, 2, testData)
+				return
+			}
+
+			require.NoError(t, err)
+			assertState(t, l, 2+len(testData)-tc.i, testData[:tc.i])
+			assertState(t, front, 0, testData[tc.i:])
+			require.NotNil(t, front.StringFunc)
+
+			// moved out slots were zeroed
+			for j := tc.i; j < len(testData); j++ {
+				require.Zero(t, l.s[(l.back+j)%l.slen])
+			}
+
+			// no shared memory
+			if front.Len() > 0 {
+				front.s[0] = 42
+				require.NotContains(t, l.s, 42)
+			}
+		})
+	}
+}
+
+func TestList_Extract(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5}
+
+	testCases := []struct {
+		i, n      int
+		err       error
+		extracted []int
+		remaining []int
+	}{
+		{i: -1, n: 1, err: ErrInvalidRange},
+		{i: 5, n: 1, err: ErrInvalidRange},
+		{i: 0, n: 6, err: ErrInvalidRange},
+		{i: 0, n: -1, err: ErrInvalidRange},
+
+		{i: 0, n: 0, extracted: []int{}, remaining: testData},
+		{i: 0, n: 2, extracted: []int{1, 2}, remaining: []int{3, 4, 5}},
+		{i: 1, n: 3, extracted: []int{2, 3, 4}, remaining: []int{1, 5}},
+		{i: 3, n: 2, extracted: []int{4, 5}, remaining: []int{1, 2, 3}},
+		{i: 0, n: 5, extracted: testData, remaining: []int{}},
+		{i: 3, n: 4, extracted: []int{4, 5, 1, 2}, remaining: []int{3}},
+		{i: 4, n: 5, extracted: []int{5, 1, 2, 3, 4}, remaining: []int{}},
+	}
+
+	for i, tc := range testCases {
+		for _, back := range []int{0, 3, 5} {
+			t.Run(fmt.Sprintf("test index #%d back=%d", i, back), func(t *testing.T) {
+				l := wrappedList(t, back, 2, testData...)
+
+				ret, err := l.Extract(tc.i, tc.n)
+				if tc.err != nil {
+					require.ErrorIs(t, err, tc.err)
+					require.Nil(t, ret)
+					assertState(t, l, 2, testData)
+					return
+				}
+
+				require.NoError(t, err)
+				assertState(t, ret, 0, tc.extracted)
+				assertState(t, l, 2+tc.n, tc.remaining)
+
+				// removed slots were zeroed
+				var nonZero int
+				for _, v := range l.s {
+					if v != 0 {
+						nonZero++
+					}
+				}
+				require.Equal(t, len(tc.remaining), nonZero)
+			})
+		}
+	}
+}
+
+func TestList_Extend(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		back1, free1 int
+		back2, free2 int
+		v1, v2       []int
+	}{
+		{},
+		{v1: []int{1, 2}},
+		{v2: []int{1, 2}},
+		{back1: 2, free1: 1, back2: 1, free2: 2, v1: []int{1, 2, 3}, v2: []int{4, 5, 6}},
+		{back1: 1, free1: 5, back2: 4, free2: 1, v1: []int{1, 2}, v2: []int{3, 4, 5}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			expected := append(slices.Clone(tc.v1), tc.v2...)
+			l1 := wrappedList(t, tc.back1, tc.free1, tc.v1...)
+			l2 := wrappedList(t, tc.back2, tc.free2, tc.v2...)
+
+			c := Concat(l1, l2)
+			assertState(t, c, 0, expected)
+			assertState(t, l1, tc.free1, tc.v1)
+			assertState(t, l2, tc.free2, tc.v2)
+
+			require.NoError(t, l1.Extend(l2))
+			assertState(t, l1, l1.Free(), expected)
+			assertState(t, l2, tc.free2, tc.v2)
+		})
+	}
+
+	l := wrappedList(t, 2, 1, 1, 2, 3)
+	require.NoError(t, l.Extend(l))
+	assertState(t, l, l.Free(), []int{1, 2, 3, 1, 2, 3})
+
+	l = wrappedList(t, 2, 1, 1, 2, 3)
+	l.MaxCap = 4
+	require.ErrorIs(t, l.Extend(New([]int{4, 5}, true)), ErrInvalidAllocation)
+	assertState(t, l, 1, []int{1, 2, 3})
+}
+
+func TestList_Resize(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5}
+
+	testCases := []struct {
+		n        int
+		err      error
+		expected []int
+	}{
+		{n: -1, err: ErrInvalidAmount},
+
+		{n: 0, expected: []int{}},
+		{n: 2, expected: []int{1, 2}},
+		{n: 5, expected: []int{1, 2, 3, 4, 5}},
+		{n: 6, expected: []int{1, 2, 3, 4, 5, 9}},
+		{n: 7, expected: []int{1, 2, 3, 4, 5, 9, 9}},
+		{n: 9, expected: []int{1, 2, 3, 4, 5, 9, 9, 9, 9}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, 4, 2, testData...)
+			err := l.Resize(tc.n, 9)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				assertState(t, l, 2, testData)
+				return
+			}
+
+			require.NoError(t, err)
+			assertState(t, l, l.Free(), tc.expected)
+			for i := l.len; i < l.slen; i++ {
+				require.Zero(t, l.s[l.abs(i)], "free element %d", i)
+			}
+
+			if tc.n <= len(testData) {
+				l := wrappedList(t, 4, 2, testData...)
+				require.NoError(t, l.Truncate(tc.n))
+				assertState(t, l, l.Free(), tc.expected)
+			}
+		})
+	}
+
+	l := wrappedList(t, 4, 2, testData...)
+	require.ErrorIs(t, l.Truncate(6), ErrInvalidAmount)
+	assertState(t, l, 2, testData)
+}
+
+func TestList_Pop(t *testing.T) {
+	t.Parallel()
+
+	l := wrappedList(t, 4, 1, 1, 2, 3, 4)
+	for i := 4; i > 0; i-- {
+		require.Equal(t, i, l.Pop())
+		assertState(t, l, 5-l.Len(), []int{1, 2, 3, 4}[:i-1])
+	}
+	require.Zero(t, l.Pop())
+	require.Equal(t, []int{0, 0, 0, 0, 0}, l.s)
+}
+
+func TestList_PopN(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5}
+
+	testCases := []struct {
+		n           int
+		err         error
+		front, back []int
+	}{
+		{n: -1, err: ErrInvalidAmount},
+		{n: 6, err: ErrInvalidAmount},
+
+		{n: 0, front: []int{}, back: []int{}},
+		{n: 2, front: []int{4, 5}, back: []int{1, 2}},
+		{n: 4, front: []int{2, 3, 4, 5}, back: []int{1, 2, 3, 4}},
+		{n: 5, front: testData, back: testData},
+	}
+
+	isZero := func(v int) bool { return v == 0 }
+
+	for i, tc := range testCases {
+		for _, back := range []int{0, 3, 6} {
+			t.Run(fmt.Sprintf("test index #%d back=%d", i, back), func(t *testing.T) {
+				l1 := wrappedList(t, back, 2, testData...)
+				l2 := wrappedList(t, back, 2, testData...)
+				got1, err1 := l1.PopN(tc.n)
+				got2, err2 := l2.PopBackN(tc.n)
+				if tc.err != nil {
+					require.ErrorIs(t, err1, tc.err)
+					require.ErrorIs(t, err2, tc.err)
+					assertState(t, l1, 2, testData)
+					assertState(t, l2, 2, testData)
+					return
+				}
+
+				require.NoError(t, err1)
+				require.NoError(t, err2)
+				require.Equal(t, tc.front, got1)
+				require.Equal(t, tc.back, got2)
+				assertState(t, l1, 2+tc.n, testData[:len(testData)-tc.n])
+				assertState(t, l2, 2+tc.n, testData[tc.n:])
+				require.Len(t, slices.DeleteFunc(slices.Clone(l1.s), isZero), l1.Len())
+				require.Len(t, slices.DeleteFunc(slices.Clone(l2.s), isZero), l2.Len())
+			})
+		}
+	}
+
+	l := wrappedList(t, 3, 1, 1, 2)
+	require.NoError(t, l.PushN(3, 4))
+	assertState(t, l, l.Free(), []int{1, 2, 3, 4})
+}
+
+func TestList_SwapRemove(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5}
+
+	testCases := []struct {
+		back, i  int
+		err      error
+		expected []int
+	}{
+		{i: -1, err: ErrInvalidPosition},
+		{i: 5, err: ErrInvalidPosition},
+
+		{i: 0, expected: []int{5, 2, 3, 4}},
+		{i: 2, expected: []int{1, 2, 5, 4}},
+		{i: 4, expected: []int{1, 2, 3, 4}},
+		{back: 4, i: 0, expected: []int{5, 2, 3, 4}},
+		{back: 4, i: 3, expected: []int{1, 2, 3, 5}},
+		{back: 2, i: 4, expected: []int{1, 2, 3, 4}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, tc.back, 1, testData...)
+			v, err := l.SwapRemove(tc.i)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				assertState(t, l, 1, testData)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testData[tc.i], v)
+			assertState(t, l, 2, tc.expected)
+			for i := l.len; i < l.slen; i++ {
+				require.Zero(t, l.s[l.abs(i)], "free element %d", i)
+			}
+		})
+	}
+}
+
+func TestList_Fill(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5}
+
+	testCases := []struct {
+		i, n     int
+		err      error
+		expected []int
+	}{
+		{i: -1, n: 1, err: ErrInvalidRange},
+		{i: 5, n: 1, err: ErrInvalidRange},
+		{i: 0, n: 6, err: ErrInvalidRange},
+		{i: 0, n: -1, err: ErrInvalidRange},
+
+		{i: 0, n: 0, expected: []int{1, 2, 3, 4, 5}},
+		{i: 0, n: 5, expected: []int{9, 9, 9, 9, 9}},
+		{i: 1, n: 3, expected: []int{1, 9, 9, 9, 5}},
+		{i: 2, n: 3, expected: []int{1, 2, 9, 9, 9}},
+		{i: 3, n: 3, expected: []int{9, 2, 3, 9, 9}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			// the ring boundary is between the second and third elements
+			l := wrappedList(t, 5, 2, testData...)
+			err := l.Fill(9, tc.i, tc.n)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				assertState(t, l, 2, testData)
+			} else {
+				require.NoError(t, err)
+				assertState(t, l, 2, tc.expected)
+			}
+		})
+	}
+}
+
+func TestList_TryApply(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5}
+	errTest := errors.New("test error")
+
+	l := wrappedList(t, 3, 2, testData...)
+	err := l.TryApply(func(v int) (int, error) {
+		if v == 4 {
+			return 0, errTest
+		}
+		return v * 10, nil
+	})
+	require.ErrorIs(t, err, errTest)
+	assertState(t, l, 2, testData)
+
+	err = l.TryApply(func(v int) (int, error) { return v * 10, nil })
+	require.NoError(t, err)
+	assertState(t, l, 2, []int{10, 20, 30, 40, 50})
+}
+
+func TestList_Filter(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5, 6, 7}
+
+	testCases := []struct {
+		keep     func(int) bool
+		expected []int
+	}{
+		{keep: func(int) bool { return true }, expected: testData},
+		{keep: func(int) bool { return false }, expected: []int{}},
+		{keep: func(v int) bool { return v%2 == 0 }, expected: []int{2, 4, 6}},
+		{keep: func(v int) bool { return v < 3 || 5 < v }, expected: []int{1, 2, 6, 7}},
+		{keep: func(v int) bool { return v != 1 }, expected: []int{2, 3, 4, 5, 6, 7}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, 5, 2, testData...)
+			s := l.s
+			removed := l.Filter(tc.keep)
+			require.Equal(t, len(testData)-len(tc.expected), removed)
+			assertState(t, l, l.Cap()-len(tc.expected), tc.expected)
+			require.Equal(t, &s[0], &l.s[0], "should not allocate")
+			for i := l.len; i < l.slen; i++ {
+				require.Zero(t, l.s[l.abs(i)], "free element %d", i)
+			}
+		})
+	}
+}
+
+func TestList_RemoveFunc(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input     []int
+		v         int
+		removed   int
+		remaining []int
+	}{
+		{input: []int{}, v: 1, remaining: []int{}},
+		{input: []int{2, 3}, v: 1, remaining: []int{2, 3}},
+		{input: []int{1, 1, 1}, v: 1, removed: 3, remaining: []int{}},
+		{input: []int{1, 2, 1, 3, 1, 4, 1}, v: 1, removed: 4, remaining: []int{2, 3, 4}},
+		{input: []int{5, 1, 2, 1, 1, 3}, v: 1, removed: 3, remaining: []int{5, 2, 3}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, 4, 2, tc.input...)
+			require.Equal(t, tc.removed, l.RemoveAll(tc.v, Eq[int]))
+			assertState(t, l, l.Cap()-len(tc.remaining), tc.remaining)
+			for i := l.len; i < l.slen; i++ {
+				require.Zero(t, l.s[l.abs(i)], "free element %d", i)
+			}
+
+			l = wrappedList(t, 4, 2, tc.input...)
+			require.Equal(t, tc.removed, l.RemoveFunc(func(v int) bool { return v == tc.v }))
+			assertState(t, l, l.Cap()-len(tc.remaining), tc.remaining)
+		})
+	}
+}
+
+func TestList_Partition(t *testing.T) {
+	t.Parallel()
+
+	even := func(v int) bool { return v%2 == 0 }
+
+	testCases := []struct {
+		input []int
+		index int
+	}{
+		{input: []int{}, index: 0},
+		{input: []int{1}, index: 0},
+		{input: []int{2}, index: 1},
+		{input: []int{1, 3, 5}, index: 0},
+		{input: []int{2, 4, 6}, index: 3},
+		{input: []int{1, 2, 3, 4, 5, 6, 7}, index: 3},
+		{input: []int{2, 1, 4, 3, 6, 5, 8}, index: 4},
+		{input: []int{1, 1, 1, 2, 2, 2}, index: 3},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, 5, 2, tc.input...)
+			index := l.Partition(even)
+			require.Equal(t, tc.index, index)
+			got := l.ToSlice()
+			for j, v := range got {
+				require.Equal(t, j < index, even(v), "element %d of %v", j, got)
+			}
+			expected := slices.Clone(tc.input)
+			slices.Sort(expected)
+			slices.Sort(got)
+			require.Equal(t, expected, got)
+			require.Equal(t, 2, l.Free())
+		})
+	}
+}
+
+func TestList_StablePartition(t *testing.T) {
+	t.Parallel()
+
+	even := func(v int) bool { return v%2 == 0 }
+
+	testCases := []struct {
+		input, expected []int
+		index           int
+	}{
+		{input: []int{}, expected: []int{}, index: 0},
+		{input: []int{1}, expected: []int{1}, index: 0},
+		{input: []int{2}, expected: []int{2}, index: 1},
+		{input: []int{1, 3, 5}, expected: []int{1, 3, 5}, index: 0},
+		{input: []int{2, 4, 6}, expected: []int{2, 4, 6}, index: 3},
+		{input: []int{1, 2, 3, 4, 5, 6, 7}, expected: []int{2, 4, 6, 1, 3, 5, 7}, index: 3},
+		{input: []int{7, 8, 5, 2, 3, 6, 1}, expected: []int{8, 2, 6, 7, 5, 3, 1}, index: 3},
+		{input: []int{1, 1, 1, 2, 2, 2}, expected: []int{2, 2, 2, 1, 1, 1}, index: 3},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, 5, 2, tc.input...)
+			require.Equal(t, tc.index, l.StablePartition(even))
+			assertState(t, l, 2, tc.expected)
+		})
+	}
+}
+
+func TestList_DedupFunc(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input, expected []int
+	}{
+		{input: []int{}, expected: []int{}},
+		{input: []int{1}, expected: []int{1}},
+		{input: []int{1, 2, 3, 4}, expected: []int{1, 2, 3, 4}},
+		{input: []int{5, 5, 5, 5, 5}, expected: []int{5}},
+		{input: []int{1, 1, 2, 3, 3, 3, 4, 1, 1}, expected: []int{1, 2, 3, 4, 1}},
+	}
+
+	eq := func(a, b int) bool { return a == b }
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, 5, 2, tc.input...)
+			s := l.s
+			removed := l.DedupFunc(eq)
+			require.Equal(t, len(tc.input)-len(tc.expected), removed)
+			assertState(t, l, l.Cap()-len(tc.expected), tc.expected)
+			require.Equal(t, &s[0], &l.s[0], "should not allocate")
+			for i := l.len; i < l.slen; i++ {
+				require.Zero(t, l.s[l.abs(i)], "free element %d", i)
+			}
+		})
+	}
+}
+
+func TestList_MoveRange(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5, 6, 7}
+
+	testCases := []struct {
+		i, n, dst int
+		err       error
+		expected  []int
+	}{
+		{i: -1, n: 2, dst: 0, err: ErrInvalidRange},
+		{i: 0, n: -1, dst: 0, err: ErrInvalidRange},
+		{i: 6, n: 2, dst: 0, err: ErrInvalidRange},
+		{i: 0, n: 2, dst: -1, err: ErrInvalidRange},
+		{i: 0, n: 2, dst: 6, err: ErrInvalidRange},
+
+		{i: 0, n: 0, dst: 7, expected: testData},
+		{i: 2, n: 3, dst: 2, expected: testData},
+		{i: 0, n: 7, dst: 0, expected: testData},
+
+		// forward
+		{i: 0, n: 2, dst: 5, expected: []int{3, 4, 5, 6, 7, 1, 2}},
+		{i: 1, n: 2, dst: 3, expected: []int{1, 4, 5, 2, 3, 6, 7}},
+		{i: 1, n: 3, dst: 2, expected: []int{1, 5, 2, 3, 4, 6, 7}},
+
+		// backward
+		{i: 5, n: 2, dst: 0, expected: []int{6, 7, 1, 2, 3, 4, 5}},
+		{i: 3, n: 2, dst: 1, expected: []int{1, 4, 5, 2, 3, 6, 7}},
+		{i: 2, n: 3, dst: 1, expected: []int{1, 3, 4, 5, 2, 6, 7}},
+	}
+
+	for i, tc := range testCases {
+		for _, back := range []int{0, 4, 8} {
+			for _, free := range []int{0, 2} {
+				t.Run(fmt.Sprintf("test index #%d back=%d free=%d", i, back, free), func(t *testing.T) {
+					l := wrappedList(t, back, free, testData...)
+					err := l.MoveRange(tc.i, tc.n, tc.dst)
+					if tc.err != nil {
+						require.ErrorIs(t, err, tc.err)
+						assertState(t, l, free, testData)
+						return
+					}
+
+					require.NoError(t, err)
+					assertState(t, l, free, tc.expected)
+					for i := l.len; i < l.slen; i++ {
+						require.Zero(t, l.s[l.abs(i)], "free element %d", i)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestList_MoveToFront(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5}
+
+	testCases := []struct {
+		i               int
+		err             error
+		toFront, toBack []int
+	}{
+		{i: -1, err: ErrInvalidPosition},
+		{i: 5, err: ErrInvalidPosition},
+
+		{i: 0, toFront: []int{2, 3, 4, 5, 1}, toBack: []int{1, 2, 3, 4, 5}},
+		{i: 2, toFront: []int{1, 2, 4, 5, 3}, toBack: []int{3, 1, 2, 4, 5}},
+		{i: 4, toFront: []int{1, 2, 3, 4, 5}, toBack: []int{5, 1, 2, 3, 4}},
+	}
+
+	for i, tc := range testCases {
+		for _, back := range []int{0, 3, 5} {
+			t.Run(fmt.Sprintf("test index #%d back=%d", i, back), func(t *testing.T) {
+				l1 := wrappedList(t, back, 2, testData...)
+				l2 := wrappedList(t, back, 2, testData...)
+				err1, err2 := l1.MoveToFront(tc.i), l2.MoveToBack(tc.i)
+				if tc.err != nil {
+					require.ErrorIs(t, err1, tc.err)
+					require.ErrorIs(t, err2, tc.err)
+					assertState(t, l1, 2, testData)
+					assertState(t, l2, 2, testData)
+					return
+				}
+
+				require.NoError(t, err1)
+				require.NoError(t, err2)
+				assertState(t, l1, 2, tc.toFront)
+				assertState(t, l2, 2, tc.toBack)
+			})
+		}
+	}
+}
+
+func TestList_RotateRange(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5, 6, 7}
+
+	testCases := []struct {
+		back, free, i, j, n int
+		err                 error
+		expected            []int
+	}{
+		{i: -1, j: 2, err: ErrInvalidRange},
+		{i: 3, j: 8, err: ErrInvalidRange},
+
+		{i: 0, j: 0, n: 3, expected: []int{1, 2, 3, 4, 5, 6, 7}},
+		{i: 1, j: 4, n: 3, expected: []int{1, 2, 3, 4, 5, 6, 7}},
+		{i: 1, j: 4, n: 1, expected: []int{1, 3, 4, 2, 5, 6, 7}},
+		{i: 1, j: 4, n: -1, expected: []int{1, 4, 2, 3, 5, 6, 7}},
+		{back: 5, i: 0, j: 7, n: 2, expected: []int{3, 4, 5, 6, 7, 1, 2}},
+		{back: 5, i: 2, j: 6, n: 3, expected: []int{1, 2, 6, 3, 4, 5, 7}},
+		{back: 7, i: 1, j: 7, n: 8, expected: []int{1, 4, 5, 6, 7, 2, 3}},
+
+		// not enough free space for either part, so it uses reversals
+		{back: 5, free: 1, i: 0, j: 7, n: 3, expected: []int{4, 5, 6, 7, 1, 2, 3}},
+		{back: 5, free: 1, i: 1, j: 7, n: 2, expected: []int{1, 4, 5, 6, 7, 2, 3}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			free := cmp.Or(tc.free, 2)
+			l := wrappedList(t, tc.back, free, testData...)
+			err := l.RotateRange(tc.i, tc.j, tc.n)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				assertState(t, l, free, testData)
+				return
+			}
+
+			require.NoError(t, err)
+			assertState(t, l, free, tc.expected)
+			for i := l.len; i < l.slen; i++ {
+				require.Zero(t, l.s[l.abs(i)], "free element %d", i)
+			}
+		})
+	}
+}
+
+func BenchmarkList_reverseRotate(b *testing.B) {
+	const size = 1 << 12
+
+	// with no free space it always uses reversals, otherwise it uses the free
+	// space as scratch
+	for _, free := range []int{0, size / 2} {
+		s := make([]int, size+free)
+		l, err := NewN(s, free/2, size)
+		require.NoError(b, err)
+
+		for _, n := range []int{1, size / 8, size / 2} {
+			b.Run(fmt.Sprintf("free=%d n=%d", free, n), func(b *testing.B) {
+				for range b.N {
+					l.reverseRotate(0, size, n)
+				}
+			})
+		}
+	}
+}
+
+func TestList_Compact(t *testing.T) {
+	t.Parallel()
+
+	var freed [][]int
+	freeFunc := func(s []int) { freed = append(freed, s) }
+
+	l := wrappedList(t, 0, 0, 1, 2, 3)
+	l.FreeFunc = freeFunc
+	require.NoError(t, l.Compact())
+	require.Empty(t, freed, "should be a nop")
+
+	l = wrappedList(t, 4, 3, 1, 2, 3)
+	l.FreeFunc = freeFunc
+	old := l.s
+	require.NoError(t, l.Compact())
+	assertState(t, l, 0, []int{1, 2, 3})
+	require.Equal(t, [][]int{old}, freed)
+	require.Equal(t, []int{0, 0, 0, 0, 0, 0}, old)
+
+	l = wrappedList(t, 4, 3, 1, 2, 3)
+	l.AllocFunc = func(int, int) ([]int, error) { return nil, errors.New("oops") }
+	require.Error(t, l.Compact())
+	assertState(t, l, 3, []int{1, 2, 3})
+}
+
+func TestList_NoZero(t *testing.T) {
+	t.Parallel()
+
+	l := wrappedList(t, 4, 3, 1, 2, 3, 4, 5)
+	l.NoZero = true
+	l.s[l.abs(-1)] = -1 // mark the free elements too
+	l.s[l.abs(-2)] = -1
+	l.s[l.abs(-3)] = -1
+
+	require.NoError(t, l.Delete(0, 2))
+	assertState(t, l, 5, []int{3, 4, 5})
+	require.Equal(t, 1, l.Filter(func(v int) bool { return v != 4 }))
+	assertState(t, l, 6, []int{3, 5})
+	v, err := l.SwapRemove(0)
+	require.NoError(t, err)
+	require.Equal(t, 3, v)
+	assertState(t, l, 7, []int{5})
+	require.Equal(t, 1, l.Clear())
+	assertState(t, l, 8, nil)
+	require.NotContains(t, l.s, 0, "should not zero any element")
+	require.True(t, l.Clone().NoZero)
+}
+
+func TestList_PushOverwrite(t *testing.T) {
+	t.Parallel()
+
+	const capacity = 5
+
+	l := Make[int](capacity)
+	for i := range capacity + 3 {
+		evicted, didEvict := l.PushOverwrite(i)
+		if i < capacity {
+			require.False(t, didEvict)
+			require.Zero(t, evicted)
+		} else {
+			require.True(t, didEvict)
+			require.Equal(t, i-capacity, evicted)
+		}
+	}
+	assertState(t, l, 0, []int{3, 4, 5, 6, 7})
+
+	l = wrappedList(t, 3, 1, 1, 2, 3)
+	_, didEvict := l.PushOverwrite(4)
+	require.False(t, didEvict)
+	evicted, didEvict := l.PushOverwrite(5)
+	require.True(t, didEvict)
+	require.Equal(t, 1, evicted)
+	assertState(t, l, 0, []int{2, 3, 4, 5})
+
+	l = New[int](nil, false)
+	evicted, didEvict = l.PushOverwrite(1)
+	require.True(t, didEvict)
+	require.Equal(t, 1, evicted)
+	assertState(t, l, 0, nil)
+}
+
+func TestList_Release(t *testing.T) {
+	t.Parallel()
+
+	var freed [][]int
+	l := wrappedList(t, 4, 3, 1, 2, 3)
+	l.FreeFunc = func(s []int) { freed = append(freed, s) }
+	old := l.s
 
-	l = New[int](nil, true)
+	l.Release()
 	assertState(t, l, 0, nil)
+	require.Equal(t, 0, l.Cap())
+	require.Equal(t, [][]int{old}, freed)
+	require.Equal(t, []int{0, 0, 0, 0, 0, 0}, old)
 
-	l = New[int](s, false)
-	assertState(t, l, len(s), nil)
+	l.Release()
+	require.Len(t, freed, 1, "should not free a nil slice")
 
-	l = New[int](s, true)
-	assertState(t, l, 0, s)
+	l.Push(4)
+	assertState(t, l, l.Cap()-1, []int{4})
 }
 
-func TestNewT(t *testing.T) {
+func TestList_CompactTo(t *testing.T) {
 	t.Parallel()
-	s := []int{1, 2, 3, 4, 5}
 
 	testCases := []struct {
-		input  []int
-		back   int
-		length int
-
-		fails    bool
-		expected []int
+		maxFree, free int
+		err           error
 	}{
-		{input: nil, back: -1, length: -1, fails: true},
-		{input: nil, back: -1, length: 0, fails: true},
-		{input: nil, back: 0, length: -1, fails: true},
-		{input: nil, back: 0, length: 1, fails: true},
-		{input: nil, back: 1, length: 0, fails: true},
-		{input: nil, back: 1, length: 1, fails: true},
-		{input: s, back: -1, length: 0, fails: true},
-		{input: s, back: 0, length: -1, fails: true},
-		{input: s, back: 0, length: len(s) + 1, fails: true},
-		{input: s, back: len(s), length: 0, fails: true},
-
-		// next index is 12
-		{input: nil, back: 0, length: 0},
-		{input: s, back: 0, length: 0},
-		{input: s, back: 0, length: 1, expected: s[:1]},
-		{input: s, back: 0, length: 3, expected: s[:3]},
-		{input: s, back: 0, length: len(s), expected: s},
-		{input: s, back: 1, length: 1, expected: s[1:2]},
-		{input: s, back: 1, length: 2, expected: s[1:3]},
-		{input: s, back: len(s) - 1, length: 1, expected: s[len(s)-1:]},
-		{input: s, back: 3, length: 4, expected: []int{4, 5, 1, 2}},
-		{input: s, back: 3, length: 5, expected: []int{4, 5, 1, 2, 3}},
-		{input: s, back: 3, length: 5, expected: []int{4, 5, 1, 2, 3}},
-		{input: s, back: 4, length: 0},
+		{maxFree: -1, free: 3, err: ErrInvalidAmount},
+		{maxFree: 0, free: 0},
+		{maxFree: 2, free: 0},
+		{maxFree: 3, free: 3},
+		{maxFree: 4, free: 3},
 	}
 
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
-			l, err := NewN[int](tc.input, tc.back, tc.length)
-			if tc.fails {
-				require.Error(t, err)
-				require.Nil(t, l)
-
+			l := wrappedList(t, 1, 3, 1, 2, 3)
+			err := l.CompactTo(tc.maxFree)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
 			} else {
 				require.NoError(t, err)
-				assertState(t, l, len(tc.input)-tc.length, tc.expected)
 			}
+			assertState(t, l, tc.free, []int{1, 2, 3})
 		})
 	}
 }
 
-func TestList_JSON(t *testing.T) {
+func TestList_Normalize(t *testing.T) {
 	t.Parallel()
 
+	testData := []int{1, 2, 3, 4, 5}
+
 	testCases := []struct {
-		// only fill in output if it differs from input and the test should not
-		// fail, otherwise we expect the same as input
-		input, output string
-		fails         bool
+		back, free int
 	}{
-		{input: "", fails: true},
-		{input: ".", fails: true},
-		{input: "1", fails: true},
-		{input: `"a"`, fails: true},
-		{input: `{}`, fails: true},
+		{back: 0, free: 0},
+		{back: 0, free: 3},
+		{back: 3, free: 0},
+		{back: 1, free: 3},
+		{back: 3, free: 3},
+		{back: 6, free: 3},
+		{back: 7, free: 3},
+	}
 
-		{input: "null", output: "[]"},
-		{input: "[]"},
-		{input: "[1]"},
-		{input: "[1, 2, 3, 4, 5, 6, 7, 8, 9]"},
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, tc.back, tc.free, testData...)
+			s := l.s
+			l.Normalize()
+			require.False(t, l.wraps())
+			require.Equal(t, 0, l.back)
+			require.Equal(t, &s[0], &l.s[0], "should not allocate")
+			assertState(t, l, tc.free, testData)
+			for i := l.len; i < l.slen; i++ {
+				require.Zero(t, l.s[l.abs(i)], "free element %d", i)
+			}
+		})
+	}
+}
+
+func TestList_Segments(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4, 5}
+
+	testCases := []struct {
+		back, free    int
+		values        []int
+		first, second []int
+	}{
+		{},
+		{back: 2, free: 3},
+		{values: testData, first: testData},
+		{free: 2, values: testData, first: testData},
+		{back: 2, free: 2, values: testData, first: testData},
+		{back: 3, free: 2, values: testData, first: []int{1, 2, 3, 4}, second: []int{5}},
+		{back: 6, free: 2, values: testData, first: []int{1}, second: []int{2, 3, 4, 5}},
+		{back: 2, values: testData, first: []int{1, 2, 3}, second: []int{4, 5}},
 	}
 
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
-			var l List[int]
-			err := json.Unmarshal([]byte(tc.input), &l)
-			if tc.fails {
-				assert.Error(t, err)
-				assert.Equal(t, 0, l.Len())
+			l := wrappedList(t, tc.back, tc.free, tc.values...)
+			first, second := l.Segments()
+			require.Equal(t, tc.first, first)
+			require.Equal(t, tc.second, second)
+			require.Equal(t, len(first), cap(first))
+			require.Equal(t, len(second), cap(second))
+		})
+	}
+}
 
-			} else {
-				// marshal both pointer non-pointer types should succeed and
-				// produce same output
-				b1, err := json.Marshal(l)
-				require.NoError(t, err)
-				b2, err := json.Marshal(&l)
-				require.NoError(t, err)
-				assert.Equal(t, b1, b2)
-				assert.JSONEq(t, cmp.Or(tc.output, tc.input), string(b1))
-			}
+func TestList_Any(t *testing.T) {
+	t.Parallel()
+
+	isEven := func(v int) bool { return v%2 == 0 }
+
+	testCases := []struct {
+		input            []int
+		any, every, none bool
+	}{
+		{input: nil, any: false, every: true, none: true},
+		{input: []int{1, 3, 5}, any: false, every: false, none: true},
+		{input: []int{1, 2, 5}, any: true, every: false, none: false},
+		{input: []int{2, 4, 6}, any: true, every: true, none: false},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, 2, 1, tc.input...)
+			require.Equal(t, tc.any, l.Any(isEven))
+			require.Equal(t, tc.every, l.Every(isEven))
+			require.Equal(t, tc.none, l.None(isEven))
 		})
 	}
+
+	var calls int
+	l := New([]int{1, 2, 3, 4}, true)
+	l.Any(func(v int) bool { calls++; return v == 2 })
+	require.Equal(t, 2, calls, "should short-circuit")
 }
 
-func TestList_StringRange(t *testing.T) {
+func TestList_IndicesFunc(t *testing.T) {
+	t.Parallel()
+
+	l := wrappedList(t, 4, 2, 5, 1, 4, 2, 3, 6)
+	require.Equal(t, []int{2, 3, 5}, l.IndicesFunc(func(v int) bool { return v%2 == 0 }))
+	require.Empty(t, l.IndicesFunc(func(v int) bool { return v > 6 }))
+	require.Equal(t, []int{0, 1, 2, 3, 4, 5}, l.IndicesFunc(func(v int) bool { return v > 0 }))
+}
+
+func TestList_CountFunc(t *testing.T) {
+	t.Parallel()
+
+	l := wrappedList(t, 4, 2, 5, 1, 4, 2, 3, 6)
+	require.Equal(t, 3, l.CountFunc(func(v int) bool { return v%2 == 0 }))
+	require.Equal(t, 0, l.CountFunc(func(v int) bool { return v > 6 }))
+	require.Equal(t, 6, l.CountFunc(func(v int) bool { return v > 0 }))
+	require.Equal(t, 0, New[int](nil, false).CountFunc(func(int) bool { return true }))
+}
+
+func TestList_MinMaxFunc(t *testing.T) {
+	t.Parallel()
+
+	type item struct{ key, id int }
+	byKey := func(a, b item) int { return cmp.Compare(a.key, b.key) }
+
+	testCases := []struct {
+		input    []item
+		min, max item
+		ok       bool
+	}{
+		{input: nil},
+		{input: []item{{5, 0}}, min: item{5, 0}, max: item{5, 0}, ok: true},
+		{input: []item{{5, 0}, {3, 1}}, min: item{3, 1}, max: item{5, 0}, ok: true},
+		{input: []item{{3, 0}, {5, 1}, {4, 2}}, min: item{3, 0}, max: item{5, 1}, ok: true},
+		{
+			input: []item{{4, 0}, {2, 1}, {9, 2}, {2, 3}, {9, 4}, {7, 5}},
+			min:   item{2, 1}, max: item{9, 2}, ok: true,
+		},
+		{
+			input: []item{{1, 0}, {1, 1}, {1, 2}, {1, 3}},
+			min:   item{1, 0}, max: item{1, 0}, ok: true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, 3, 2, tc.input...)
+
+			v, ok := l.MinFunc(byKey)
+			require.Equal(t, tc.min, v)
+			require.Equal(t, tc.ok, ok)
+
+			v, ok = l.MaxFunc(byKey)
+			require.Equal(t, tc.max, v)
+			require.Equal(t, tc.ok, ok)
+
+			min, max, ok := l.MinMaxFunc(byKey)
+			require.Equal(t, tc.min, min)
+			require.Equal(t, tc.max, max)
+			require.Equal(t, tc.ok, ok)
+		})
+	}
+}
+
+func TestList_ToSlice(t *testing.T) {
+	t.Parallel()
+
+	l := wrappedList(t, 3, 2, 1, 2, 3, 4, 5)
+	s := l.ToSlice()
+	require.Equal(t, []int{1, 2, 3, 4, 5}, s)
+	s[0] = 9
+	assertState(t, l, 2, []int{1, 2, 3, 4, 5})
+
+	require.Equal(t, []int{}, New[int](nil, false).ToSlice())
+}
+
+func TestList_HeadTail(t *testing.T) {
 	t.Parallel()
 
 	testData := []int{1, 2, 3, 4, 5}
-	l := New(testData, true)
 
 	testCases := []struct {
-		i, n     int
-		expected string
+		n          int
+		head, tail []int
+	}{
+		{n: -1, head: []int{}, tail: []int{}},
+		{n: 0, head: []int{}, tail: []int{}},
+		{n: 3, head: []int{1, 2, 3}, tail: []int{3, 4, 5}},
+		{n: 5, head: testData, tail: testData},
+		{n: 9, head: testData, tail: testData},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, 3, 2, testData...)
+			head, tail := l.Head(tc.n), l.Tail(tc.n)
+			require.Equal(t, tc.head, head)
+			require.Equal(t, tc.tail, tail)
+			if len(head) > 0 {
+				head[0], tail[0] = 9, 9
+			}
+			assertState(t, l, 2, testData)
+		})
+	}
+
+	l := New[int](nil, false)
+	require.Equal(t, []int{}, l.Head(1))
+	require.Equal(t, []int{}, l.Tail(1))
+}
+
+func TestList_AppendTo(t *testing.T) {
+	t.Parallel()
+
+	l := wrappedList(t, 3, 2, 1, 2, 3, 4, 5)
+
+	buf := make([]int, 2, 10)
+	buf[0], buf[1] = 8, 9
+	s := l.AppendTo(buf)
+	require.Equal(t, []int{8, 9, 1, 2, 3, 4, 5}, s)
+	require.Equal(t, &buf[0], &s[0], "should reuse the capacity of dst")
+	s[2] = 7
+	assertState(t, l, 2, []int{1, 2, 3, 4, 5})
+
+	s = l.AppendTo(nil)
+	require.Equal(t, []int{1, 2, 3, 4, 5}, s)
+}
+
+func TestList_Grow(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3}
+
+	testCases := []struct {
+		min, max int
 		err      error
+		realloc  bool
 	}{
-		{i: -1, n: 0, err: ErrInvalidRange},
-		{i: 0, n: -1, err: ErrInvalidRange},
-		{i: l.Len(), n: 0, err: ErrInvalidRange},
-		{i: 0, n: l.Len() + 1, err: ErrInvalidRange},
+		{min: -1, max: -1, err: ErrInvalidAmount},
+		{min: 2, max: 1, err: ErrInvalidAmount},
+
+		{min: 0, max: -1},
+		{min: 2, max: -1},
+		{min: 3, max: -1, realloc: true},
+		{min: 0, max: 1, realloc: true},
+		{min: 2, max: 3},
+		{min: 4, max: 4, realloc: true},
+	}
 
-		{i: 0, n: 0, expected: "[]"},
-		{i: 0, n: l.Len(), expected: "[1, 2, 3, 4, 5]"},
-		{i: l.Len() - 1, n: l.Len(), expected: "[5, 1, 2, 3, 4]"},
-		{i: 3, n: 1, expected: "[4]"},
-		{i: 3, n: 0, expected: "[]"},
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			l := wrappedList(t, 4, 2, testData...)
+			s := l.s
+
+			var err error
+			if tc.max < 0 {
+				err = l.Grow(tc.min)
+			} else {
+				err = l.GrowRange(tc.min, tc.max)
+			}
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				assertState(t, l, 2, testData)
+				return
+			}
+
+			require.NoError(t, err)
+			assertState(t, l, l.Free(), testData)
+			require.LessOrEqual(t, tc.min, l.Free())
+			if tc.max >= 0 {
+				require.LessOrEqual(t, l.Free(), tc.max)
+			}
+			require.Equal(t, tc.realloc, &s[0] != &l.s[0])
+		})
+	}
+}
+
+func TestList_overflow(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3}
+
+	l := wrappedList(t, 4, 2, testData...)
+	require.ErrorIs(t, l.Grow(math.MaxInt), ErrTooLarge)
+	require.ErrorIs(t, l.Grow(math.MaxInt-2), ErrTooLarge)
+	require.ErrorIs(t, l.GrowRange(math.MaxInt, math.MaxInt), ErrTooLarge)
+	require.ErrorIs(t, l.GrowExact(math.MaxInt), ErrTooLarge)
+	assertState(t, l, 2, testData)
+
+	require.NoError(t, l.GrowRange(3, math.MaxInt))
+	assertState(t, l, l.Free(), testData)
+	require.LessOrEqual(t, 3, l.Free())
+
+	// sizes that don't overflow int, but are too large for a []int
+	l = New([]int{1}, true)
+	require.ErrorIs(t, l.Grow(math.MaxInt-10), ErrTooLarge)
+	require.ErrorIs(t, l.Grow(math.MaxInt/2), ErrTooLarge)
+	require.ErrorIs(t, l.Grow(math.MaxInt/8), ErrTooLarge)
+	require.ErrorIs(t, l.GrowExact(math.MaxInt/2), ErrTooLarge)
+	require.ErrorIs(t, l.Resize(math.MaxInt-10, 0), ErrTooLarge)
+	assertState(t, l, 0, []int{1})
+	_, err := AllocDefault[int](math.MaxInt/2, -1)
+	require.ErrorIs(t, err, ErrTooLarge)
+
+	// zero-sized elements allow creating huge slices without allocating
+	huge := make([]struct{}, math.MaxInt-1)
+	e := wrappedList(t, 1, 1, struct{}{}, struct{}{})
+	require.ErrorIs(t, e.Insert(1, huge...), ErrTooLarge)
+	require.ErrorIs(t, e.Append(huge...), ErrTooLarge)
+	require.NoError(t, e.Replace(0, 2, huge...))
+	require.Equal(t, math.MaxInt-1, e.Len())
+
+	s, err := AllocDefault[struct{}](math.MaxInt-1, -1)
+	require.NoError(t, err)
+	require.Len(t, s, math.MaxInt)
+}
+
+func TestList_GrowExact(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3}
+
+	testCases := []struct {
+		n       int
+		err     error
+		realloc bool
+	}{
+		{n: -1, err: ErrInvalidAmount},
+
+		{n: 0},
+		{n: 2},
+		{n: 3, realloc: true},
+		{n: 10, realloc: true},
 	}
 
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
-			result, err := l.StringRange(tc.i, tc.n)
+			l := wrappedList(t, 4, 2, testData...)
+			s := l.s
+
+			err := l.GrowExact(tc.n)
 			if tc.err != nil {
-				require.Empty(t, result)
 				require.ErrorIs(t, err, tc.err)
+				assertState(t, l, 2, testData)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.realloc, &s[0] != &l.s[0])
+			if tc.realloc {
+				assertState(t, l, tc.n, testData)
+				require.Equal(t, l.Len()+tc.n, l.Cap())
 			} else {
-				require.NoError(t, err)
-				require.Equal(t, tc.expected, result)
+				assertState(t, l, 2, testData)
+			}
+		})
+	}
+}
+
+func TestList_MaxCap(t *testing.T) {
+	t.Parallel()
+
+	var gotMax []int
+	l := wrappedList(t, 2, 1, 1, 2, 3)
+	l.MaxCap = 6
+	l.AllocFunc = func(min, max int) ([]int, error) {
+		gotMax = append(gotMax, max)
+		return AllocDefault[int](min, max)
+	}
+
+	require.NoError(t, l.Append(4, 5))
+	assertState(t, l, 1, []int{1, 2, 3, 4, 5})
+	require.Equal(t, []int{6}, gotMax)
+
+	require.NoError(t, l.Append(6))
+	assertState(t, l, 0, []int{1, 2, 3, 4, 5, 6})
+
+	require.ErrorIs(t, l.Append(7), ErrInvalidAllocation)
+	require.ErrorIs(t, l.Insert(0, 0), ErrInvalidAllocation)
+	require.ErrorIs(t, l.Grow(1), ErrInvalidAllocation)
+	require.ErrorIs(t, l.GrowRange(1, 10), ErrInvalidAllocation)
+	l.Push(7)
+	assertState(t, l, 0, []int{1, 2, 3, 4, 5, 6})
+	require.Equal(t, []int{6}, gotMax)
+
+	require.Equal(t, 6, l.Clone().MaxCap)
+
+	l.MaxCap = 0
+	require.NoError(t, l.Append(7))
+	require.Equal(t, []int{6, -1}, gotMax)
+	assertState(t, l, l.Free(), []int{1, 2, 3, 4, 5, 6, 7})
+}
+
+func TestList_MaxCap_decode(t *testing.T) {
+	t.Parallel()
+
+	var allocs int
+	newList := func() *List[int] {
+		l := wrappedList(t, 1, 1, 1, 2)
+		l.MaxCap = 4
+		l.AllocFunc = func(min, max int) ([]int, error) {
+			allocs++
+			return AllocDefault[int](min, max)
+		}
+		return l
+	}
+
+	l := newList()
+	err := json.Unmarshal([]byte(`[1, 2, 3, 4, 5, 6, 7, 8, 9, 10]`), l)
+	require.ErrorIs(t, err, ErrInvalidAllocation)
+	assertState(t, l, l.Cap(), nil)
+	require.LessOrEqual(t, l.Cap(), 4)
+	require.NoError(t, json.Unmarshal([]byte(`[5, 6, 7, 8]`), l))
+	assertState(t, l, 0, []int{5, 6, 7, 8})
+	require.Equal(t, 1, allocs)
+
+	var b bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&b).Encode(New([]int{1, 2, 3, 4, 5, 6, 7, 8}, true)))
+	l = newList()
+	err = gob.NewDecoder(bytes.NewReader(b.Bytes())).Decode(l)
+	require.ErrorIs(t, err, ErrInvalidAllocation)
+	assertState(t, l, 1, []int{1, 2})
+
+	b.Reset()
+	require.NoError(t, gob.NewEncoder(&b).Encode(New([]int{1, 2, 3}, true)))
+	require.NoError(t, gob.NewDecoder(&b).Decode(l))
+	assertState(t, l, l.Free(), []int{1, 2, 3})
+	require.LessOrEqual(t, l.Cap(), 4)
+	require.Equal(t, 2, allocs)
+}
+
+func TestList_CompactIfWrapped(t *testing.T) {
+	t.Parallel()
+
+	l := wrappedList(t, 1, 2, 1, 2, 3)
+	s := l.s
+	require.NoError(t, l.CompactIfWrapped())
+	require.Equal(t, []int{0, 1, 2, 3, 0}, s, "elements should not move")
+	assertState(t, l, 2, []int{1, 2, 3})
+
+	l = wrappedList(t, 3, 2, 1, 2, 3)
+	require.True(t, l.wraps()) // dumb-proof
+	require.NoError(t, l.CompactIfWrapped())
+	require.False(t, l.wraps())
+	require.Equal(t, 0, l.back)
+	assertState(t, l, 0, []int{1, 2, 3})
+}
+
+func TestList_At(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{1, 2, 3, 4}
+
+	for back := range 6 {
+		t.Run(fmt.Sprintf("back=%d", back), func(t *testing.T) {
+			l := wrappedList(t, back, 2, testData...)
+			for i := -len(testData); i < 2*len(testData); i++ {
+				require.Equal(t, testData[fix(len(testData), i)], l.At(i), "i=%d", i)
 			}
+			require.Equal(t, 1, l.Back())
+			require.Equal(t, 4, l.Front())
+			require.Equal(t, "[1, 2, 3, 4]", l.String())
+			require.True(t, l.Ordered(cmp.Compare[int]).IsSorted())
 		})
 	}
+
+	require.Zero(t, New[int](nil, false).At(0))
+}
+
+func TestList_Peek(t *testing.T) {
+	t.Parallel()
+
+	l := New[int](nil, false)
+	v, ok := l.PeekBack()
+	require.False(t, ok)
+	require.Zero(t, v)
+	v, ok = l.PeekFront()
+	require.False(t, ok)
+	require.Zero(t, v)
+
+	l = wrappedList(t, 2, 1, 0)
+	v, ok = l.PeekBack()
+	require.True(t, ok)
+	require.Zero(t, v)
+	v, ok = l.PeekFront()
+	require.True(t, ok)
+	require.Zero(t, v)
+
+	l = wrappedList(t, 2, 1, 1, 2, 3)
+	v, ok = l.PeekBack()
+	require.True(t, ok)
+	require.Equal(t, 1, v)
+	v, ok = l.PeekFront()
+	require.True(t, ok)
+	require.Equal(t, 3, v)
 }
 
 func TestView_abs(t *testing.T) {
 	t.Parallel()
 
 	testCases := []struct {
-		slen, i, result int
+		slen, back, i, result int
 	}{
-		{slen: 0, i: 0, result: 0},
-
-		// TODO: add cases
+		{slen: 0, back: 0, i: 0, result: 0},
+
+		{slen: 5, back: 0, i: 0, result: 0},
+		{slen: 5, back: 0, i: 4, result: 4},
+		{slen: 5, back: 2, i: 0, result: 2},
+		{slen: 5, back: 2, i: 2, result: 4},
+		{slen: 5, back: 2, i: 3, result: 0}, // lands exactly on slen
+		{slen: 5, back: 2, i: 4, result: 1},
+		{slen: 5, back: 4, i: 1, result: 0}, // lands exactly on slen
+		{slen: 5, back: 4, i: 4, result: 3},
 	}
 
 	var v view
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
-			v.slen = tc.slen
+			v.slen, v.back = tc.slen, tc.back
 			result := v.abs(tc.i)
 			assert.Equal(t, tc.result, result)
 		})
@@ -200,19 +2562,40 @@ func TestView_fixAbs(t *testing.T) {
 	t.Parallel()
 
 	testCases := []struct {
-		slen, len, i, result int
+		slen, len, back, i, result int
 	}{
-		{slen: 0, len: 0, i: 0, result: 0},
-
-		// TODO: add cases
+		{slen: 0, len: 0, back: 0, i: 0, result: 0},
+		{slen: 5, len: 0, back: 3, i: 2, result: 0},
+
+		{slen: 5, len: 5, back: 0, i: 0, result: 0},
+		{slen: 5, len: 5, back: 0, i: 4, result: 4},
+		{slen: 5, len: 5, back: 0, i: -1, result: 4},
+		{slen: 5, len: 5, back: 0, i: 5, result: 0},
+
+		{slen: 5, len: 4, back: 2, i: 0, result: 2},
+		{slen: 5, len: 4, back: 2, i: 2, result: 4},
+		{slen: 5, len: 4, back: 2, i: 3, result: 0},  // lands exactly on slen
+		{slen: 5, len: 4, back: 2, i: -1, result: 0}, // lands exactly on slen
+		{slen: 5, len: 4, back: 2, i: -2, result: 4},
+		{slen: 5, len: 4, back: 2, i: 4, result: 2},
+		{slen: 5, len: 4, back: 2, i: 7, result: 0},
+
+		{slen: 5, len: 3, back: 4, i: 0, result: 4},
+		{slen: 5, len: 3, back: 4, i: 1, result: 0}, // lands exactly on slen
+		{slen: 5, len: 3, back: 4, i: 2, result: 1},
+		{slen: 5, len: 3, back: 4, i: -1, result: 1},
+		{slen: 5, len: 3, back: 4, i: -3, result: 4},
 	}
 
 	var v view
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
-			v.slen, v.len = tc.slen, tc.len
+			v.slen, v.len, v.back = tc.slen, tc.len, tc.back
 			result := v.fixAbs(tc.i)
 			assert.Equal(t, tc.result, result)
+			if tc.len > 0 {
+				assert.Equal(t, v.abs(fix(tc.len, tc.i)), result)
+			}
 		})
 	}
 }
@@ -226,7 +2609,13 @@ func TestView_wraps(t *testing.T) {
 	}{
 		{slen: 0, len: 0, back: 0, result: false},
 
-		// TODO: add cases
+		{slen: 5, len: 0, back: 4, result: false},
+		{slen: 5, len: 5, back: 0, result: false},
+		{slen: 5, len: 3, back: 2, result: false},
+		{slen: 5, len: 4, back: 2, result: true},
+		{slen: 5, len: 2, back: 4, result: true},
+		{slen: 5, len: 1, back: 4, result: false},
+		{slen: 5, len: 5, back: 1, result: true},
 	}
 
 	var v view
@@ -332,8 +2721,18 @@ func TestSelfWrapCopy(t *testing.T) {
 		expected []int
 	}{
 		{i: 0, n: 0, m: 0, expected: []int{1, 2, 3, 4, 5}},
-
-		// TODO: add cases
+		{i: 0, n: 2, m: 0, expected: []int{1, 2, 3, 4, 5}},
+		{i: 0, n: 0, m: 2, expected: []int{1, 2, 3, 4, 5}},
+		{i: 0, n: 4, m: 2, expected: []int{1, 2, 3, 4, 5}},
+		{i: 0, n: 4, m: -2, expected: []int{1, 2, 3, 4, 5}},
+		{i: 0, n: 2, m: 1, expected: []int{1, 1, 2, 4, 5}},
+		{i: 1, n: 2, m: -1, expected: []int{2, 3, 3, 4, 5}},
+		{i: 3, n: 2, m: 2, expected: []int{4, 5, 3, 4, 5}},
+		{i: 4, n: 2, m: 1, expected: []int{5, 1, 3, 4, 5}},
+		{i: 0, n: 2, m: -1, expected: []int{2, 2, 3, 4, 1}},
+		{i: 1, n: 3, m: 2, expected: []int{4, 2, 3, 2, 3}},
+		{i: -1, n: 3, m: -2, expected: []int{1, 2, 5, 1, 2}},
+		{i: 3, n: 4, m: 1, expected: []int{5, 1, 2, 4, 4}},
 	}
 
 	for i, tc := range testCases {
@@ -352,16 +2751,22 @@ func TestWrapCopy(t *testing.T) {
 	const testDataLen = 5
 	s1, s2 := make([]int, testDataLen), make([]int, testDataLen)
 	for i := range testDataLen {
-		s1[i], s2[i] = 1, 2
+		s1[i], s2[i] = i+1, 0
 	}
 
 	testCases := []struct {
 		i1, i2, n, copied int
 		expected          []int
 	}{
-		{i1: 0, i2: 0, n: 0, copied: 0, expected: []int{2, 2, 2, 2, 2}},
-
-		// TODO: add cases
+		{i1: 0, i2: 0, n: 0, copied: 0, expected: []int{0, 0, 0, 0, 0}},
+		{i1: 0, i2: 0, n: -1, copied: 0, expected: []int{0, 0, 0, 0, 0}},
+		{i1: 0, i2: 0, n: 2, copied: 2, expected: []int{1, 2, 0, 0, 0}},
+		{i1: 1, i2: 2, n: 3, copied: 3, expected: []int{0, 0, 2, 3, 4}},
+		{i1: 3, i2: 0, n: 3, copied: 3, expected: []int{4, 5, 1, 0, 0}},
+		{i1: 0, i2: 4, n: 3, copied: 3, expected: []int{2, 3, 0, 0, 1}},
+		{i1: 4, i2: 3, n: 4, copied: 4, expected: []int{2, 3, 0, 5, 1}},
+		{i1: -1, i2: -2, n: 2, copied: 2, expected: []int{0, 0, 0, 5, 1}},
+		{i1: 0, i2: 0, n: 7, copied: 5, expected: []int{1, 2, 3, 4, 5}},
 	}
 
 	witness := slices.Clone(s1)
@@ -392,8 +2797,13 @@ func TestWrapClear(t *testing.T) {
 		expected      []int
 	}{
 		{i: 0, n: 0, cleared: 0, expected: []int{1, 1, 1, 1, 1}},
-
-		// TODO: add cases
+		{i: 0, n: 2, cleared: 2, expected: []int{0, 0, 1, 1, 1}},
+		{i: 3, n: 2, cleared: 2, expected: []int{1, 1, 1, 0, 0}},
+		{i: 3, n: 4, cleared: 4, expected: []int{0, 0, 1, 0, 0}},
+		{i: -1, n: 2, cleared: 2, expected: []int{0, 1, 1, 1, 0}},
+		{i: 1, n: -3, cleared: 3, expected: []int{0, 1, 1, 0, 0}},
+		{i: 2, n: 5, cleared: 5, expected: []int{0, 0, 0, 0, 0}},
+		{i: 2, n: -7, cleared: 5, expected: []int{0, 0, 0, 0, 0}},
 	}
 
 	for i, tc := range testCases {
@@ -453,3 +2863,57 @@ func TestFix(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkList_NoZero(b *testing.B) {
+	const size = 1 << 10
+
+	for _, noZero := range []bool{false, true} {
+		b.Run(fmt.Sprintf("NoZero=%v", noZero), func(b *testing.B) {
+			l := New(make([]int, size), false)
+			l.NoZero = noZero
+			s := make([]int, size/2)
+			for range b.N {
+				l.Replace(0, l.Len(), s...)
+				l.Replace(0, size/4)
+				l.Clear()
+			}
+		})
+	}
+}
+
+func BenchmarkList_MarshalJSON(b *testing.B) {
+	const size = 10_000
+
+	s := make([]int, size)
+	for i := range s {
+		s[i] = i * 1000
+	}
+	l, err := NewN(s, size/2, size)
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	for range b.N {
+		if _, err := l.MarshalJSON(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkList_UnmarshalJSON(b *testing.B) {
+	const size = 10_000
+
+	s := make([]int, size)
+	for i := range s {
+		s[i] = i * 1000
+	}
+	data, err := json.Marshal(s)
+	require.NoError(b, err)
+
+	var l List[int]
+	b.ReportAllocs()
+	for range b.N {
+		if err := l.UnmarshalJSON(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package list
 
 import (
+	"cmp"
+	"math/rand"
 	"slices"
 	"sort"
 )
@@ -18,6 +20,28 @@ type CompareFunc[T any] func(x T, y T) int
 // Inverse inverts the operands of f to achieve the opposite ordering.
 func (f CompareFunc[T]) Inverse(x, y T) int { return f(y, x) }
 
+// Natural returns a CompareFunc with the natural ordering of T, which is
+// cmp.Compare.
+func Natural[T cmp.Ordered]() CompareFunc[T] { return cmp.Compare[T] }
+
+// ByKey returns a CompareFunc that compares the keys returned by key with
+// their natural ordering.
+func ByKey[T any, K cmp.Ordered](key func(T) K) CompareFunc[T] {
+	return func(x, y T) int { return cmp.Compare(key(x), key(y)) }
+}
+
+// Then returns a CompareFunc that compares with f, and if f reports that both
+// operands are equal, then compares them with g. This allows ordering by
+// multiple keys.
+func (f CompareFunc[T]) Then(g CompareFunc[T]) CompareFunc[T] {
+	return func(x, y T) int {
+		if c := f(x, y); c != 0 {
+			return c
+		}
+		return g(x, y)
+	}
+}
+
 // Ordered is a List whose elements can be compared, and it satisfies
 // sort.Interface.
 type Ordered[T any] struct {
@@ -58,6 +82,14 @@ func (o Ordered[T]) Invert() Ordered[T] {
 	return o
 }
 
+// WithCompare returns an Ordered with the given comparison function, reusing
+// the same underlying data. The data is not reordered, so Sort or Heap().Init
+// would typically be called next to establish the new ordering.
+func (o Ordered[T]) WithCompare(cmp CompareFunc[T]) Ordered[T] {
+	o.cmp = cmp
+	return o
+}
+
 // Less returns whether the given element at i is less than the element at j.
 // If the list is empty, it always returns false.
 func (o Ordered[T]) Less(i, j int) bool {
@@ -94,6 +126,40 @@ func (o Ordered[T]) SortStable() {
 	}
 }
 
+// SortRange is like Sort, but only sorts the elements in the range [i, j).
+func (o Ordered[T]) SortRange(i, j int) error {
+	if !o.rngBound(i, j) {
+		return ErrInvalidRange
+	}
+	if i == j {
+		return nil
+	}
+
+	ll := *o.List
+	ll.back, ll.len = o.abs(i), j-i
+	if !ll.wraps() {
+		slices.SortFunc(ll.s[ll.back:ll.back+ll.len], o.cmp)
+	} else {
+		sort.Sort(rangeSorter[T]{&ll, o.cmp})
+	}
+
+	return nil
+}
+
+// IsSortedRange is like IsSorted, but only checks the elements in the range
+// [i, j). If the range is invalid, it returns false.
+func (o Ordered[T]) IsSortedRange(i, j int) bool {
+	if !o.rngBound(i, j) {
+		return false
+	}
+	for i++; i < j; i++ {
+		if o.cmp(o.s[o.abs(i-1)], o.s[o.abs(i)]) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
 // IsSorted reports whether data is sorted in ascending order.
 func (o Ordered[T]) IsSorted() bool {
 	if !o.wraps() {
@@ -102,18 +168,320 @@ func (o Ordered[T]) IsSorted() bool {
 	return sort.IsSorted(o)
 }
 
+// IsStrictlySorted reports whether data is sorted in ascending order and has
+// no equal elements.
+func (o Ordered[T]) IsStrictlySorted() bool {
+	for i := 1; i < o.len; i++ {
+		if o.cmp(o.s[o.abs(i-1)], o.s[o.abs(i)]) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Min returns the minimal element and true, or the zero value and false if the
+// list is empty. It's O(n), since checking whether the data is sorted would
+// be O(n) as well. If the data is known to be sorted, then the minimal element
+// can be obtained in O(1) with Val(0).
+func (o Ordered[T]) Min() (T, bool) { return o.MinFunc(o.cmp) }
+
+// Max returns the maximal element and true, or the zero value and false if the
+// list is empty. It's O(n), since checking whether the data is sorted would
+// be O(n) as well. If the data is known to be sorted, then the maximal element
+// can be obtained in O(1) with Val(Len()-1).
+func (o Ordered[T]) Max() (T, bool) { return o.MaxFunc(o.cmp) }
+
+// Nth returns the element that would be at position k if the list was sorted.
+// It uses quickselect, which is expected to be O(n), and reorders the
+// elements of the list in the process, so that the elements at positions
+// lower than k are less than or equal to the returned one, and those at
+// positions greater than k are greater than or equal to it. If k is out of
+// range, it returns ErrInvalidPosition.
+func (o Ordered[T]) Nth(k int) (v T, err error) {
+	if !o.elBound(k) {
+		return v, ErrInvalidPosition
+	}
+
+	lo, hi := 0, o.len
+	for hi-lo > 1 {
+		pivot := o.s[o.abs(lo+rand.Intn(hi-lo))]
+
+		// three-way partition of [lo, hi) into elements less than, equal to
+		// and greater than pivot, so that runs of equal elements don't
+		// degrade performance
+		lt, i, gt := lo, lo, hi
+		for i < gt {
+			switch c := o.cmp(o.s[o.abs(i)], pivot); {
+			case c < 0:
+				o.Swap(lt, i)
+				lt++
+				i++
+			case c > 0:
+				gt--
+				o.Swap(i, gt)
+			default:
+				i++
+			}
+		}
+
+		switch {
+		case k < lt:
+			hi = lt
+		case gt <= k:
+			lo = gt
+		default:
+			return o.s[o.abs(k)], nil
+		}
+	}
+
+	return o.s[o.abs(k)], nil
+}
+
+// Median returns the element that would be at position (Len()-1)/2 if the
+// list was sorted, and true. For an even number of elements, this is the
+// lower of the two middle elements. It reorders the elements of the list like
+// Nth. If the list is empty, it returns the zero value and false.
+func (o Ordered[T]) Median() (T, bool) {
+	v, err := o.Nth((o.len - 1) / 2)
+	return v, err == nil
+}
+
 // Find uses binary search to find and return the smallest index i at which the
-// list element is >= v.
+// list element is >= v. The returned bool reports whether the element at i
+// equals v.
 func (o Ordered[T]) Find(v T) (i int, found bool) {
 	return sort.Find(o.len, func(i int) int {
-		return o.cmp(o.At(i), v)
+		return o.cmp(v, o.s[o.abs(i)])
 	})
 }
 
+// LowerBound uses binary search to find and return the smallest index i at
+// which the list element is >= v, or Len() if there is none. The data is
+// expected to be sorted.
+func (o Ordered[T]) LowerBound(v T) int {
+	i, _ := o.Find(v)
+	return i
+}
+
+// UpperBound uses binary search to find and return the smallest index i at
+// which the list element is > v, or Len() if there is none. The data is
+// expected to be sorted.
+func (o Ordered[T]) UpperBound(v T) int {
+	return sort.Search(o.len, func(i int) bool {
+		return o.cmp(v, o.s[o.abs(i)]) < 0
+	})
+}
+
+// EqualRange returns the range [lo, hi) of the elements equal to v. If there
+// are none, then lo==hi and it's the position where v would be inserted. The
+// data is expected to be sorted.
+func (o Ordered[T]) EqualRange(v T) (lo, hi int) {
+	return o.LowerBound(v), o.UpperBound(v)
+}
+
 // Contains returns whether v is found in the data.
 func (o Ordered[T]) Contains(v T) bool {
+	_, found := o.Find(v)
+	return found
+}
+
+// Count returns the number of elements equal to v. It uses binary search, so
+// the data is expected to be sorted.
+func (o Ordered[T]) Count(v T) int {
+	lo, hi := o.EqualRange(v)
+	return hi - lo
+}
+
+// SortedIndexOf returns the smallest position of an element equal to v, or -1
+// if there is none. It uses binary search, so the data is expected to be
+// sorted.
+func (o Ordered[T]) SortedIndexOf(v T) int {
 	if i, found := o.Find(v); found {
-		return o.cmp(o.At(i), v) == 0
+		return i
 	}
-	return false
+	return -1
+}
+
+// InsertTopK inserts v in its sorted position, and then removes the smallest
+// elements so that the list keeps at most the k greatest ones. It returns
+// whether v was kept in the list. If v is equal to the smallest element of a
+// list that already has k elements, then it's not inserted. The data is
+// expected to be sorted.
+func (o Ordered[T]) InsertTopK(v T, k int) bool {
+	if k < 1 {
+		return false
+	}
+
+	i, _ := o.Find(v)
+	drop := max(o.len+1-k, 0)
+	if i < drop {
+		// v itself would be dropped
+		o.Delete(0, drop-1)
+		return false
+	}
+	o.Delete(0, drop)
+
+	return o.Insert(i-drop, v) == nil
+}
+
+// InsertSorted uses binary search to insert v in its sorted position, and
+// returns that position. If there are elements equal to v, then v is inserted
+// after all of them, so the order in which equal elements were inserted is
+// preserved. If the element could not be inserted, it returns -1. The data is
+// expected to be sorted.
+func (o Ordered[T]) InsertSorted(v T) int {
+	i := o.UpperBound(v)
+	if o.Insert(i, v) != nil {
+		return -1
+	}
+	return i
+}
+
+// NearestTo uses binary search to find the element closest to v, as measured
+// by dist, and returns it along with its position and true. If two elements
+// are at the same distance, the one with the smallest position is returned.
+// If the list is empty, it returns the zero value, -1 and false.
+func (o Ordered[T]) NearestTo(v T, dist func(a, b T) float64) (T, int, bool) {
+	if o.len == 0 {
+		var zero T
+		return zero, -1, false
+	}
+
+	i, found := o.Find(v)
+	if found || i == 0 {
+		return o.s[o.abs(i)], i, true
+	}
+	prev := o.s[o.abs(i-1)]
+	if i == o.len {
+		return prev, i - 1, true
+	}
+
+	next := o.s[o.abs(i)]
+	if dist(v, next) < dist(v, prev) {
+		return next, i, true
+	}
+	return prev, i - 1, true
+}
+
+// Dedup removes the elements that are equal to the previous one, and returns
+// the number of elements removed. If the data is sorted, then the result has
+// no equal elements.
+func (o Ordered[T]) Dedup() int {
+	return o.DedupFunc(func(a, b T) bool { return o.cmp(a, b) == 0 })
+}
+
+// Merge adds the elements of other to the list, keeping it sorted. Both are
+// expected to be sorted. Elements of other that are equal to elements of the
+// list are placed after them. The list is grown once and the merge is done in
+// place, in O(n+m).
+func (o Ordered[T]) Merge(other Ordered[T]) error {
+	if other.List == o.List {
+		other.List = other.Clone()
+	}
+	if err := o.Grow(other.len); err != nil {
+		return err
+	}
+
+	// merge from the front so that the elements not yet merged are never
+	// overwritten
+	i, j := o.len-1, other.len-1
+	o.len += other.len
+	for k := o.len - 1; 0 <= j; k-- {
+		y := other.s[other.abs(j)]
+		if 0 <= i {
+			if x := o.s[o.abs(i)]; o.cmp(x, y) > 0 {
+				o.s[o.abs(k)] = x
+				i--
+				continue
+			}
+		}
+		o.s[o.abs(k)] = y
+		j--
+	}
+
+	return nil
+}
+
+// Union returns a new Ordered with the elements that are in either o or other.
+// Both are expected to be sorted and without equal elements, and so will be
+// the result. It's O(n+m).
+func (o Ordered[T]) Union(other Ordered[T]) Ordered[T] {
+	return o.setOp(other, true, true, true)
+}
+
+// Intersection returns a new Ordered with the elements that are in both o and
+// other. Both are expected to be sorted and without equal elements, and so
+// will be the result. It's O(n+m).
+func (o Ordered[T]) Intersection(other Ordered[T]) Ordered[T] {
+	return o.setOp(other, false, false, true)
+}
+
+// Difference returns a new Ordered with the elements that are in o but not in
+// other. Both are expected to be sorted and without equal elements, and so
+// will be the result. It's O(n+m).
+func (o Ordered[T]) Difference(other Ordered[T]) Ordered[T] {
+	return o.setOp(other, true, false, false)
+}
+
+// SymmetricDifference returns a new Ordered with the elements that are in
+// either o or other, but not in both. Both are expected to be sorted, and so
+// will be the result. It's O(n+m).
+func (o Ordered[T]) SymmetricDifference(other Ordered[T]) Ordered[T] {
+	return o.setOp(other, true, true, false)
+}
+
+// setOp walks o and other in order, and returns a new Ordered with the
+// elements only in o if onlyO is true, the elements only in other if
+// onlyOther is true, and the elements in both if both is true.
+func (o Ordered[T]) setOp(other Ordered[T], onlyO, onlyOther, both bool) Ordered[T] {
+	s := make([]T, 0, o.len+other.len)
+	i, j := 0, 0
+	for i < o.len && j < other.len {
+		x, y := o.s[o.abs(i)], other.s[other.abs(j)]
+		switch c := o.cmp(x, y); {
+		case c < 0:
+			if onlyO {
+				s = append(s, x)
+			}
+			i++
+		case c > 0:
+			if onlyOther {
+				s = append(s, y)
+			}
+			j++
+		default:
+			if both {
+				s = append(s, x)
+			}
+			i++
+			j++
+		}
+	}
+	for ; onlyO && i < o.len; i++ {
+		s = append(s, o.s[o.abs(i)])
+	}
+	for ; onlyOther && j < other.len; j++ {
+		s = append(s, other.s[other.abs(j)])
+	}
+
+	return New(s, true).Ordered(o.cmp)
+}
+
+// rangeSorter implements sort.Interface by directly accessing the underlying
+// slice of a list, whose view may have been changed to only span a range of
+// the original list.
+type rangeSorter[T any] struct {
+	l   *List[T]
+	cmp CompareFunc[T]
+}
+
+func (r rangeSorter[T]) Len() int { return r.l.len }
+
+func (r rangeSorter[T]) Less(i, j int) bool {
+	return r.cmp(r.l.s[r.l.abs(i)], r.l.s[r.l.abs(j)]) < 0
+}
+
+func (r rangeSorter[T]) Swap(i, j int) {
+	i, j = r.l.abs(i), r.l.abs(j)
+	r.l.s[i], r.l.s[j] = r.l.s[j], r.l.s[i]
 }
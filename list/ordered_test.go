@@ -0,0 +1,566 @@
+package list
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNatural(t *testing.T) {
+	t.Parallel()
+
+	h := NewHeap(Natural[int]())
+	for _, v := range []int{5, 3, 8, 1} {
+		h.Push(v)
+	}
+	assert.Equal(t, 1, h.Pop())
+	assert.Equal(t, 3, h.Pop())
+
+	o := New([]string{"b", "c", "a"}, true).Ordered(Natural[string]())
+	o.Sort()
+	assert.Equal(t, []string{"a", "b", "c"}, o.ToSlice())
+}
+
+func TestByKey(t *testing.T) {
+	t.Parallel()
+
+	type kv struct {
+		k string
+		v int
+	}
+	o := New([]kv{{"x", 3}, {"y", 1}, {"z", 2}}, true).
+		Ordered(ByKey(func(e kv) int { return e.v }))
+	o.Sort()
+	assert.Equal(t, []kv{{"y", 1}, {"z", 2}, {"x", 3}}, o.ToSlice())
+
+	o.Ordered(ByKey(func(e kv) string { return e.k })).Sort()
+	assert.Equal(t, []kv{{"x", 3}, {"y", 1}, {"z", 2}}, o.ToSlice())
+}
+
+func TestOrdered_WithCompare(t *testing.T) {
+	t.Parallel()
+
+	type kv struct {
+		k string
+		v int
+	}
+	o := New([]kv{{"x", 3}, {"y", 1}, {"z", 2}}, true).
+		Ordered(ByKey(func(e kv) int { return e.v }))
+	o.Sort()
+	assert.Equal(t, []kv{{"y", 1}, {"z", 2}, {"x", 3}}, o.ToSlice())
+
+	byKey := o.WithCompare(ByKey(func(e kv) string { return e.k }))
+	assert.Equal(t, []kv{{"y", 1}, {"z", 2}, {"x", 3}}, byKey.ToSlice(),
+		"should not reorder")
+	byKey.Sort()
+	assert.Equal(t, []kv{{"x", 3}, {"y", 1}, {"z", 2}}, o.ToSlice(),
+		"should share the data")
+	assert.True(t, byKey.IsSorted())
+	assert.False(t, o.IsSorted())
+}
+
+func TestCompareFunc_Then(t *testing.T) {
+	t.Parallel()
+
+	type person struct {
+		name string
+		age  int
+	}
+	byAge := CompareFunc[person](func(a, b person) int { return cmp.Compare(a.age, b.age) })
+	byName := CompareFunc[person](func(a, b person) int { return cmp.Compare(a.name, b.name) })
+
+	input := []person{{"c", 30}, {"b", 20}, {"a", 30}, {"d", 20}, {"e", 10}}
+
+	o := New(slices.Clone(input), true).Ordered(byAge.Then(byName))
+	o.Sort()
+	assert.Equal(t, []person{
+		{"e", 10}, {"b", 20}, {"d", 20}, {"a", 30}, {"c", 30},
+	}, o.ToSlice())
+
+	o = New(slices.Clone(input), true).Ordered(byAge.Then(byName).Inverse)
+	o.Sort()
+	assert.Equal(t, []person{
+		{"c", 30}, {"a", 30}, {"d", 20}, {"b", 20}, {"e", 10},
+	}, o.ToSlice())
+}
+
+func TestOrdered_IsStrictlySorted(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input    []int
+		expected bool
+	}{
+		{input: nil, expected: true},
+		{input: []int{1}, expected: true},
+		{input: []int{1, 2, 5, 9}, expected: true},
+
+		{input: []int{1, 2, 2, 9}},
+		{input: []int{2, 2}},
+		{input: []int{1, 3, 2}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			o := wrappedList(t, len(tc.input)/2, 1, tc.input...).Ordered(cmp.Compare[int])
+			assert.Equal(t, tc.expected, o.IsStrictlySorted())
+		})
+	}
+}
+
+func TestOrdered_Min(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input    []int
+		min, max int
+		ok       bool
+	}{
+		{input: nil},
+		{input: []int{4}, min: 4, max: 4, ok: true},
+		{input: []int{1, 2, 3, 4}, min: 1, max: 4, ok: true},
+		{input: []int{3, 1, 4, 2}, min: 1, max: 4, ok: true},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			o := wrappedList(t, 3, 1, tc.input...).Ordered(cmp.Compare[int])
+			min, ok := o.Min()
+			assert.Equal(t, tc.min, min)
+			assert.Equal(t, tc.ok, ok)
+			max, ok := o.Max()
+			assert.Equal(t, tc.max, max)
+			assert.Equal(t, tc.ok, ok)
+		})
+	}
+}
+
+func TestOrdered_Nth(t *testing.T) {
+	t.Parallel()
+
+	o := NewOrdered(cmp.Compare[int])
+	_, err := o.Nth(0)
+	assert.ErrorIs(t, err, ErrInvalidPosition)
+	_, ok := o.Median()
+	assert.False(t, ok)
+
+	r := rand.New(rand.NewSource(1))
+	for i := range 50 {
+		s := make([]int, r.Intn(100)+1)
+		for j := range s {
+			s[j] = r.Intn(len(s))
+		}
+		sorted := New(slices.Clone(s), true).Ordered(cmp.Compare[int])
+		sorted.Sort()
+
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			o := wrappedList(t, len(s)/3, 2, s...).Ordered(cmp.Compare[int])
+			_, err := o.Nth(-1)
+			assert.ErrorIs(t, err, ErrInvalidPosition)
+			_, err = o.Nth(len(s))
+			assert.ErrorIs(t, err, ErrInvalidPosition)
+
+			for k := range len(s) {
+				v, err := o.Nth(k)
+				require.NoError(t, err)
+				require.Equal(t, sorted.At(k), v, "k=%d", k)
+			}
+
+			v, ok := o.Median()
+			assert.True(t, ok)
+			assert.Equal(t, sorted.At((len(s)-1)/2), v)
+		})
+	}
+}
+
+func TestOrdered_SortRange(t *testing.T) {
+	t.Parallel()
+
+	testData := []int{7, 6, 5, 4, 3, 2, 1}
+
+	testCases := []struct {
+		back, i, j int
+		err        error
+		expected   []int
+	}{
+		{i: -1, j: 2, err: ErrInvalidRange},
+		{i: 3, j: 8, err: ErrInvalidRange},
+		{i: 4, j: 3, err: ErrInvalidRange},
+
+		{i: 3, j: 3, expected: []int{7, 6, 5, 4, 3, 2, 1}},
+		{i: 0, j: 7, expected: []int{1, 2, 3, 4, 5, 6, 7}},
+		{i: 2, j: 5, expected: []int{7, 6, 3, 4, 5, 2, 1}},
+		{back: 5, i: 0, j: 7, expected: []int{1, 2, 3, 4, 5, 6, 7}},
+		{back: 5, i: 1, j: 6, expected: []int{7, 2, 3, 4, 5, 6, 1}},
+		{back: 5, i: 4, j: 7, expected: []int{7, 6, 5, 4, 1, 2, 3}},
+		{back: 5, i: 5, j: 7, expected: []int{7, 6, 5, 4, 3, 1, 2}},
+		{back: 5, i: 0, j: 3, expected: []int{5, 6, 7, 4, 3, 2, 1}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			o := wrappedList(t, tc.back, 2, testData...).Ordered(cmp.Compare[int])
+			err := o.SortRange(tc.i, tc.j)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				assert.False(t, o.IsSortedRange(tc.i, tc.j))
+				assert.Equal(t, testData, o.ToSlice())
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, o.IsSortedRange(tc.i, tc.j))
+			assert.Equal(t, tc.expected, o.ToSlice())
+		})
+	}
+
+	o := wrappedList(t, 5, 2, 1, 2, 3, 5, 4, 6).Ordered(cmp.Compare[int])
+	assert.True(t, o.IsSortedRange(0, 4))
+	assert.True(t, o.IsSortedRange(4, 6))
+	assert.False(t, o.IsSortedRange(2, 5))
+}
+
+func TestOrdered_Find(t *testing.T) {
+	t.Parallel()
+
+	o := wrappedList(t, 3, 2, 1, 3, 3, 5, 8).Ordered(cmp.Compare[int])
+
+	testCases := []struct {
+		v, i  int
+		found bool
+	}{
+		{v: 0, i: 0},
+		{v: 1, i: 0, found: true},
+		{v: 2, i: 1},
+		{v: 3, i: 1, found: true},
+		{v: 4, i: 3},
+		{v: 8, i: 4, found: true},
+		{v: 9, i: 5},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			i, found := o.Find(tc.v)
+			assert.Equal(t, tc.i, i)
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.found, o.Contains(tc.v))
+		})
+	}
+}
+
+func TestOrdered_Count(t *testing.T) {
+	t.Parallel()
+
+	o := wrappedList(t, 4, 2, 1, 3, 3, 3, 5, 8, 8).Ordered(cmp.Compare[int])
+
+	testCases := []struct {
+		v, n int
+	}{
+		{v: 0, n: 0},
+		{v: 1, n: 1},
+		{v: 2, n: 0},
+		{v: 3, n: 3},
+		{v: 5, n: 1},
+		{v: 8, n: 2},
+		{v: 9, n: 0},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			assert.Equal(t, tc.n, o.Count(tc.v))
+		})
+	}
+}
+
+func TestOrdered_SortedIndexOf(t *testing.T) {
+	t.Parallel()
+
+	o := wrappedList(t, 4, 2, 1, 3, 3, 3, 5, 8).Ordered(cmp.Compare[int])
+
+	testCases := []struct {
+		v, i int
+	}{
+		{v: 0, i: -1},
+		{v: 1, i: 0},
+		{v: 2, i: -1},
+		{v: 3, i: 1},
+		{v: 5, i: 4},
+		{v: 8, i: 5},
+		{v: 9, i: -1},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			assert.Equal(t, tc.i, o.SortedIndexOf(tc.v))
+		})
+	}
+
+	assert.Equal(t, -1, NewOrdered(cmp.Compare[int]).SortedIndexOf(1))
+}
+
+func TestOrdered_InsertTopK(t *testing.T) {
+	t.Parallel()
+
+	const k = 10
+	r := rand.New(rand.NewSource(1))
+	o := NewOrdered(cmp.Compare[int])
+	var all []int
+	for range 1000 {
+		v := r.Intn(500)
+		all = append(all, v)
+		retained := o.InsertTopK(v, k)
+		require.LessOrEqual(t, o.Len(), k)
+		require.True(t, slices.IsSorted(o.ToSlice()))
+		if retained {
+			require.True(t, o.Contains(v))
+		}
+	}
+	slices.Sort(all)
+	assert.Equal(t, all[len(all)-k:], o.ToSlice())
+
+	o = New([]int{3, 5, 7}, true).Ordered(cmp.Compare[int])
+	assert.False(t, o.InsertTopK(1, 3))
+	assert.False(t, o.InsertTopK(3, 3))
+	assert.Equal(t, []int{3, 5, 7}, o.ToSlice())
+	assert.True(t, o.InsertTopK(6, 3))
+	assert.Equal(t, []int{5, 6, 7}, o.ToSlice())
+	assert.True(t, o.InsertTopK(9, 2))
+	assert.Equal(t, []int{7, 9}, o.ToSlice())
+	assert.False(t, o.InsertTopK(8, 0))
+	assert.False(t, o.InsertTopK(6, 1))
+	assert.Equal(t, []int{9}, o.ToSlice())
+}
+
+func TestOrdered_EqualRange(t *testing.T) {
+	t.Parallel()
+
+	o := wrappedList(t, 4, 2, 1, 3, 3, 3, 5, 7, 7).Ordered(cmp.Compare[int])
+
+	testCases := []struct {
+		v, lo, hi int
+	}{
+		{v: 0, lo: 0, hi: 0},
+		{v: 1, lo: 0, hi: 1},
+		{v: 2, lo: 1, hi: 1},
+		{v: 3, lo: 1, hi: 4},
+		{v: 4, lo: 4, hi: 4},
+		{v: 5, lo: 4, hi: 5},
+		{v: 7, lo: 5, hi: 7},
+		{v: 8, lo: 7, hi: 7},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			assert.Equal(t, tc.lo, o.LowerBound(tc.v))
+			assert.Equal(t, tc.hi, o.UpperBound(tc.v))
+			lo, hi := o.EqualRange(tc.v)
+			assert.Equal(t, tc.lo, lo)
+			assert.Equal(t, tc.hi, hi)
+			assert.Equal(t, tc.hi-tc.lo, o.Count(tc.v))
+		})
+	}
+}
+
+func TestOrdered_InsertSorted(t *testing.T) {
+	t.Parallel()
+
+	type kv struct{ k, v int }
+	byKey := func(a, b kv) int { return cmp.Compare(a.k, b.k) }
+
+	o := wrappedList(t, 2, 2, kv{2, 0}, kv{4, 0}, kv{6, 0}).Ordered(byKey)
+	assert.Equal(t, 2, o.InsertSorted(kv{5, 0}))
+	assert.Equal(t, 4, o.InsertSorted(kv{7, 0}))
+	assert.Equal(t, 0, o.InsertSorted(kv{1, 0}))
+	assert.Equal(t, 3, o.InsertSorted(kv{4, 1}))
+	assert.Equal(t, 4, o.InsertSorted(kv{4, 2}))
+	assert.Equal(t, []kv{
+		{1, 0}, {2, 0}, {4, 0}, {4, 1}, {4, 2}, {5, 0}, {6, 0}, {7, 0},
+	}, o.ToSlice())
+
+	o = NewOrdered(byKey)
+	assert.Equal(t, 0, o.InsertSorted(kv{3, 0}))
+	assert.Equal(t, []kv{{3, 0}}, o.ToSlice())
+}
+
+func TestOrdered_NearestTo(t *testing.T) {
+	t.Parallel()
+
+	dist := func(a, b int) float64 { return math.Abs(float64(a - b)) }
+	o := wrappedList(t, 3, 2, 10, 20, 30, 40).Ordered(cmp.Compare[int])
+
+	testCases := []struct {
+		v, expected, i int
+	}{
+		{v: -5, expected: 10, i: 0},
+		{v: 10, expected: 10, i: 0},
+		{v: 14, expected: 10, i: 0},
+		{v: 15, expected: 10, i: 0},
+		{v: 16, expected: 20, i: 1},
+		{v: 30, expected: 30, i: 2},
+		{v: 39, expected: 40, i: 3},
+		{v: 100, expected: 40, i: 3},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			v, i, ok := o.NearestTo(tc.v, dist)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expected, v)
+			assert.Equal(t, tc.i, i)
+		})
+	}
+
+	v, i, ok := NewOrdered(cmp.Compare[int]).NearestTo(1, dist)
+	assert.False(t, ok)
+	assert.Equal(t, 0, v)
+	assert.Equal(t, -1, i)
+}
+
+func TestOrdered_Dedup(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input, expected []int
+	}{
+		{input: []int{}, expected: []int{}},
+		{input: []int{1, 2, 3, 4}, expected: []int{1, 2, 3, 4}},
+		{input: []int{3, 3, 3, 3}, expected: []int{3}},
+		{input: []int{1, 1, 2, 3, 3, 3, 4, 5, 5}, expected: []int{1, 2, 3, 4, 5}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			o := wrappedList(t, 3, 1, tc.input...).Ordered(cmp.Compare[int])
+			assert.Equal(t, len(tc.input)-len(tc.expected), o.Dedup())
+			assert.Equal(t, tc.expected, o.ToSlice())
+		})
+	}
+}
+
+func TestOrdered_Merge(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		a, b, expected []int
+	}{
+		{expected: []int{}},
+		{a: []int{1, 2}, expected: []int{1, 2}},
+		{b: []int{1, 2}, expected: []int{1, 2}},
+		{a: []int{1, 3, 5}, b: []int{2, 4, 6}, expected: []int{1, 2, 3, 4, 5, 6}},
+		{a: []int{1, 2, 3, 4, 5}, b: []int{0, 3}, expected: []int{0, 1, 2, 3, 3, 4, 5}},
+		{a: []int{4}, b: []int{1, 2, 3, 5, 6}, expected: []int{1, 2, 3, 4, 5, 6}},
+		{a: []int{7, 8}, b: []int{1, 2}, expected: []int{1, 2, 7, 8}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			for free := range 4 {
+				a := wrappedList(t, 2, free, tc.a...).Ordered(cmp.Compare[int])
+				b := wrappedList(t, 3, 2, tc.b...).Ordered(cmp.Compare[int])
+				require.NoError(t, a.Merge(b))
+				assert.Equal(t, tc.expected, a.ToSlice())
+				assert.Equal(t, len(tc.b), b.Len())
+			}
+		})
+	}
+
+	type kv struct{ k, v int }
+	byKey := func(a, b kv) int { return cmp.Compare(a.k, b.k) }
+	a := New([]kv{{1, 0}, {2, 0}}, true).Ordered(byKey)
+	b := New([]kv{{1, 1}, {2, 1}}, true).Ordered(byKey)
+	require.NoError(t, a.Merge(b))
+	assert.Equal(t, []kv{{1, 0}, {1, 1}, {2, 0}, {2, 1}}, a.ToSlice())
+
+	require.NoError(t, b.Merge(b))
+	assert.Equal(t, []kv{{1, 1}, {1, 1}, {2, 1}, {2, 1}}, b.ToSlice())
+}
+
+func TestOrdered_SymmetricDifference(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		a, b, expected []int
+	}{
+		{expected: []int{}},
+		{a: []int{1, 2}, expected: []int{1, 2}},
+		{b: []int{1, 2}, expected: []int{1, 2}},
+		{a: []int{1, 2, 3}, b: []int{1, 2, 3}, expected: []int{}},
+		{a: []int{1, 3, 5}, b: []int{2, 4, 6}, expected: []int{1, 2, 3, 4, 5, 6}},
+		{a: []int{1, 2, 3, 4}, b: []int{3, 4, 5, 6}, expected: []int{1, 2, 5, 6}},
+		{a: []int{1, 4, 7}, b: []int{2, 4, 6, 8}, expected: []int{1, 2, 6, 7, 8}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			a := wrappedList(t, 2, 1, tc.a...).Ordered(cmp.Compare[int])
+			b := wrappedList(t, 3, 2, tc.b...).Ordered(cmp.Compare[int])
+			got := a.SymmetricDifference(b)
+			assert.Equal(t, tc.expected, got.ToSlice())
+			assert.Equal(t, tc.expected, b.SymmetricDifference(a).ToSlice())
+		})
+	}
+}
+
+func TestOrdered_setOps(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		a, b                             []int
+		union, intersection, diff, rdiff []int
+	}{
+		{
+			union: []int{}, intersection: []int{}, diff: []int{}, rdiff: []int{},
+		},
+		{
+			a:     []int{1, 2},
+			union: []int{1, 2}, intersection: []int{}, diff: []int{1, 2}, rdiff: []int{},
+		},
+		{
+			a: []int{1, 3, 5}, b: []int{2, 4, 6},
+			union:        []int{1, 2, 3, 4, 5, 6},
+			intersection: []int{},
+			diff:         []int{1, 3, 5},
+			rdiff:        []int{2, 4, 6},
+		},
+		{
+			a: []int{1, 2, 3}, b: []int{1, 2, 3},
+			union:        []int{1, 2, 3},
+			intersection: []int{1, 2, 3},
+			diff:         []int{},
+			rdiff:        []int{},
+		},
+		{
+			a: []int{1, 2, 3, 4}, b: []int{3, 4, 5, 6},
+			union:        []int{1, 2, 3, 4, 5, 6},
+			intersection: []int{3, 4},
+			diff:         []int{1, 2},
+			rdiff:        []int{5, 6},
+		},
+		{
+			a: []int{1, 4, 7}, b: []int{2, 4, 6, 8},
+			union:        []int{1, 2, 4, 6, 7, 8},
+			intersection: []int{4},
+			diff:         []int{1, 7},
+			rdiff:        []int{2, 6, 8},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test index #%d", i), func(t *testing.T) {
+			a := wrappedList(t, 2, 1, tc.a...).Ordered(cmp.Compare[int])
+			b := wrappedList(t, 3, 2, tc.b...).Ordered(cmp.Compare[int])
+
+			assert.Equal(t, tc.union, a.Union(b).ToSlice())
+			assert.Equal(t, tc.union, b.Union(a).ToSlice())
+			assert.Equal(t, tc.intersection, a.Intersection(b).ToSlice())
+			assert.Equal(t, tc.intersection, b.Intersection(a).ToSlice())
+			assert.Equal(t, tc.diff, a.Difference(b).ToSlice())
+			assert.Equal(t, tc.rdiff, b.Difference(a).ToSlice())
+		})
+	}
+}
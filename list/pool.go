@@ -0,0 +1,23 @@
+package list
+
+import "sync"
+
+// PooledAlloc returns an AllocFunc and a FreeFunc that share slices through
+// the given pool. The AllocFunc takes a slice from the pool if it's large
+// enough, and otherwise allocates a new one with AllocDefault. The FreeFunc
+// puts slices back into the pool. Values in the pool that are not of type
+// *[]T are ignored.
+func PooledAlloc[T any](pool *sync.Pool) (AllocFunc[T], func([]T)) {
+	alloc := func(min, max int) ([]T, error) {
+		if p, _ := pool.Get().(*[]T); p != nil && min <= cap(*p) {
+			s := (*p)[:cap(*p)]
+			return s[:FixAllocSize(len(s), max)], nil
+		}
+		return AllocDefault[T](min, max)
+	}
+	free := func(s []T) {
+		s = s[:cap(s)]
+		pool.Put(&s)
+	}
+	return alloc, free
+}
@@ -0,0 +1,59 @@
+package list
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPooledAlloc(t *testing.T) {
+	t.Parallel()
+
+	pool := new(sync.Pool)
+	alloc, free := PooledAlloc[int](pool)
+
+	s, err := alloc(3, -1)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(s), 3)
+
+	free(s)
+	ss, err := alloc(2, 2)
+	require.NoError(t, err)
+	require.Len(t, ss, 2)
+
+	free(ss)
+	ss, err = alloc(len(s)+1, -1)
+	require.NoError(t, err)
+	require.Greater(t, len(ss), len(s))
+
+	pool.Put("not a slice")
+	_, err = alloc(1, -1)
+	require.NoError(t, err)
+
+	// grow lists several times, so the slices freed by one list are reused by
+	// the following ones
+	var reused int
+	seen := map[*int]bool{}
+	for range 20 {
+		l := New[int](nil, false)
+		l.AllocFunc, l.FreeFunc = alloc, free
+		var cur *int
+		for i := range 100 {
+			l.Push(i)
+			if p := &l.s[0]; p != cur {
+				cur = p
+				if seen[p] {
+					reused++
+				}
+				seen[p] = true
+			}
+		}
+		for i := range 100 {
+			v, _ := l.Val(i)
+			require.Equal(t, i, v)
+		}
+		l.Release()
+	}
+	require.NotZero(t, reused)
+}
@@ -0,0 +1,147 @@
+package list
+
+import "sync"
+
+// SyncList wraps a List to make it safe for concurrent use. Methods that only
+// read the list take a read lock, and the rest take a write lock. Iterators
+// and slices obtained from the wrapped list are not protected, so use Do to
+// iterate or make compound operations while holding the lock.
+type SyncList[T any] struct {
+	mu sync.RWMutex
+	l  *List[T]
+}
+
+// NewSyncList returns a new SyncList wrapping l. If l is nil, a new empty List
+// is used. The list should not be used directly after calling this function.
+func NewSyncList[T any](l *List[T]) *SyncList[T] {
+	if l == nil {
+		l = new(List[T])
+	}
+	return &SyncList[T]{l: l}
+}
+
+// Do calls f with the wrapped list while holding the write lock. The list
+// should not be retained or used after f returns.
+func (s *SyncList[T]) Do(f func(*List[T])) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(s.l)
+}
+
+// Len returns the number of elements in the list.
+func (s *SyncList[T]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.l.Len()
+}
+
+// Cap returns the current total capacity.
+func (s *SyncList[T]) Cap() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.l.Cap()
+}
+
+// At is like List.At.
+func (s *SyncList[T]) At(i int) T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.l.At(i)
+}
+
+// Val is like List.Val.
+func (s *SyncList[T]) Val(i int) (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.l.Val(i)
+}
+
+// CopyTo is like List.CopyTo.
+func (s *SyncList[T]) CopyTo(dst []T, i, n int) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.l.CopyTo(dst, i, n)
+}
+
+// ToSlice is like List.ToSlice.
+func (s *SyncList[T]) ToSlice() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.l.ToSlice()
+}
+
+// String is like List.String.
+func (s *SyncList[T]) String() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.l.String()
+}
+
+// Push is like List.Push.
+func (s *SyncList[T]) Push(v T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.l.Push(v)
+}
+
+// Pop removes the element at the front of the list and returns it and true.
+// If the list is empty, it returns the zero value and false. Unlike List.Pop,
+// it reports whether an element was removed, since the length of the list
+// could change between calling Len and Pop.
+func (s *SyncList[T]) Pop() (v T, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.l.len == 0 {
+		return v, false
+	}
+	return s.l.Pop(), true
+}
+
+// Insert is like List.Insert.
+func (s *SyncList[T]) Insert(i int, v ...T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.l.Insert(i, v...)
+}
+
+// Append is like List.Append.
+func (s *SyncList[T]) Append(v ...T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.l.Append(v...)
+}
+
+// Delete is like List.Delete.
+func (s *SyncList[T]) Delete(i, j int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.l.Delete(i, j)
+}
+
+// Replace is like List.Replace.
+func (s *SyncList[T]) Replace(i, j int, v ...T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.l.Replace(i, j, v...)
+}
+
+// SetAt is like List.SetAt.
+func (s *SyncList[T]) SetAt(i int, v T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.l.SetAt(i, v)
+}
+
+// Clear is like List.Clear.
+func (s *SyncList[T]) Clear() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.l.Clear()
+}
+
+// Grow is like List.Grow.
+func (s *SyncList[T]) Grow(n int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.l.Grow(n)
+}
@@ -0,0 +1,73 @@
+package list
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncList(t *testing.T) {
+	t.Parallel()
+
+	const pushers, poppers, perPusher = 4, 4, 1000
+
+	s := NewSyncList[int](nil)
+	var wg sync.WaitGroup
+	popped := make([][]int, poppers)
+	for p := range pushers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perPusher {
+				s.Push(p*perPusher + i)
+				_ = s.Len()
+				_, _ = s.Val(0)
+			}
+		}()
+	}
+	for p := range poppers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perPusher / 2 {
+				if v, ok := s.Pop(); ok {
+					popped[p] = append(popped[p], v)
+				}
+				_ = s.String()
+			}
+		}()
+	}
+	wg.Wait()
+
+	all := s.ToSlice()
+	for _, p := range popped {
+		all = append(all, p...)
+	}
+	slices.Sort(all)
+	expected := make([]int, pushers*perPusher)
+	for i := range expected {
+		expected[i] = i
+	}
+	require.Equal(t, expected, all)
+
+	s = NewSyncList(New([]int{1, 2, 3}, true))
+	require.NoError(t, s.Insert(0, 0))
+	require.NoError(t, s.Append(4))
+	require.NoError(t, s.Replace(1, 2, 5))
+	require.NoError(t, s.Delete(0, 1))
+	require.NoError(t, s.SetAt(0, 6))
+	require.Equal(t, []int{6, 2, 3, 4}, s.ToSlice())
+	require.Equal(t, 6, s.At(0))
+	dst := make([]int, 2)
+	require.NoError(t, s.CopyTo(dst, 1, 2))
+	require.Equal(t, []int{2, 3}, dst)
+	s.Do(func(l *List[int]) { l.Reverse() })
+	require.Equal(t, "[4, 3, 2, 6]", s.String())
+	require.NoError(t, s.Grow(10))
+	require.LessOrEqual(t, 14, s.Cap())
+	require.Equal(t, 4, s.Clear())
+	_, ok := s.Pop()
+	require.False(t, ok)
+}